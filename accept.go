@@ -37,10 +37,19 @@ func srtAcceptImpl(lsn C.SRTSOCKET, addr *C.struct_sockaddr, addrlen *C.int) (C.
 }
 
 // Accept an incoming connection
-func (s SrtSocket) Accept() (*SrtSocket, *net.UDPAddr, error) {
+func (s *SrtSocket) Accept() (*SrtSocket, *net.UDPAddr, error) {
+	if s.isClosed() {
+		return nil, nil, &SrtSocketClosed{}
+	}
+	socket, addr, err := s.accept(nil)
+	return socket, addr, s.closedError(err)
+}
+
+// accept - Accept a connection, giving up when done is closed
+func (s *SrtSocket) accept(done <-chan struct{}) (*SrtSocket, *net.UDPAddr, error) {
 	var err error
 	if !s.blocking {
-		err = s.pd.wait(ModeRead)
+		err = s.pd.waitDone(ModeRead, done)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -55,7 +64,7 @@ func (s SrtSocket) Accept() (*SrtSocket, *net.UDPAddr, error) {
 		return nil, nil, fmt.Errorf("srt accept, error accepting the connection: %w", srtGetAndClearError())
 	}
 
-	newSocket, err := newFromSocket(&s, socket)
+	newSocket, err := newFromSocket(s, socket)
 	if err != nil {
 		return nil, nil, fmt.Errorf("new socket could not be created: %w", err)
 	}
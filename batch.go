@@ -0,0 +1,230 @@
+package srtgo
+
+/*
+#cgo LDFLAGS: -lsrt
+#include <srt/srt.h>
+
+// Receive up to count messages in slots of stride bytes of buf, until the
+// socket has no more data ready. Only the error of the first message is
+// reported: a later one is left to the next call.
+int srt_recvmsg_batch(SRTSOCKET u, char* buf, int stride, int count, int* lens, int checkready, int *srterror, int *syserror)
+{
+	int i;
+	for (i = 0; i < count; i++) {
+		if (i > 0 && checkready) {
+			//Blocking sockets would wait for the next message
+			int events = 0;
+			int len = sizeof(events);
+			if (srt_getsockflag(u, SRTO_EVENT, &events, &len) < 0 || !(events & SRT_EPOLL_IN)) {
+				srt_clearlasterror();
+				break;
+			}
+		}
+		int ret = srt_recvmsg2(u, buf + (size_t)i * stride, stride, NULL);
+		if (ret < 0) {
+			if (i == 0) {
+				*srterror = srt_getlasterror(syserror);
+				return ret;
+			}
+			srt_clearlasterror();
+			break;
+		}
+		lens[i] = ret;
+	}
+	return i;
+}
+
+// Send the count messages stored one after the other in buf, stopping at the
+// first error
+int srt_sendmsg_batch(SRTSOCKET u, const char* buf, const int* lens, int count, int *srterror, int *syserror)
+{
+	int i;
+	for (i = 0; i < count; i++) {
+		if (srt_sendmsg2(u, buf, lens[i], NULL) < 0) {
+			*srterror = srt_getlasterror(syserror);
+			break;
+		}
+		buf += lens[i];
+	}
+	return i;
+}
+
+*/
+import "C"
+import (
+	"errors"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+// batchBuffer - Contiguous memory the messages of a batch are copied through.
+// The C helpers cannot work on the buffers of the caller directly: cgo does not
+// allow passing C memory holding Go pointers, such as an array of the buffers,
+// so one call could only reach one buffer. Copying a message in Go costs far
+// less than the cgo call per message the batch saves.
+type batchBuffer struct {
+	data []byte
+	lens []C.int
+}
+
+var batchBufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(batchBuffer)
+	},
+}
+
+func getBatchBuffer(size, count int) *batchBuffer {
+	bb := batchBufferPool.Get().(*batchBuffer)
+	if cap(bb.data) < size {
+		bb.data = make([]byte, size)
+	}
+	if cap(bb.lens) < count {
+		bb.lens = make([]C.int, count)
+	}
+	bb.data = bb.data[:size]
+	bb.lens = bb.lens[:count]
+	return bb
+}
+
+func srtRecvBatchImpl(u C.SRTSOCKET, bb *batchBuffer, stride int, checkReady bool) (n int, err error) {
+	srterr := C.int(0)
+	syserr := C.int(0)
+	check := C.int(0)
+	if checkReady {
+		check = 1
+	}
+	n = int(C.srt_recvmsg_batch(u, (*C.char)(unsafe.Pointer(&bb.data[0])), C.int(stride), C.int(len(bb.lens)),
+		&bb.lens[0], check, &srterr, &syserr))
+	if n < 0 {
+		srterror := SRTErrno(srterr)
+		if syserr < 0 {
+			srterror.wrapSysErr(syscall.Errno(syserr))
+		}
+		err = srterror
+		n = 0
+	}
+	return
+}
+
+func srtSendBatchImpl(u C.SRTSOCKET, data []byte, lens []C.int) (n int, err error) {
+	srterr := C.int(0)
+	syserr := C.int(0)
+	n = int(C.srt_sendmsg_batch(u, (*C.char)(unsafe.Pointer(&data[0])), &lens[0], C.int(len(lens)), &srterr, &syserr))
+	if n < len(lens) {
+		srterror := SRTErrno(srterr)
+		if syserr < 0 {
+			srterror.wrapSysErr(syscall.Errno(syserr))
+		}
+		err = srterror
+	}
+	return
+}
+
+// ReadBatch - Read up to len(bufs) messages from the SRT socket with a single
+// cgo call, waiting for the first one like Read, then taking the ones that are
+// ready without waiting. The number n of messages read is returned, and each
+// of bufs[:n] is resliced to the length of its message: reslice them to their
+// capacity before reusing them for another call.
+//
+// Messages are received in slots of the length of the smallest buffer, which
+// must be able to hold a whole message (the payload size in live mode).
+// ReadBatch is meant for message mode, live or with messageapi.
+func (s *SrtSocket) ReadBatch(bufs [][]byte) (int, error) {
+	if s.isClosed() {
+		return 0, &SrtSocketClosed{}
+	}
+	n, err := s.readBatch(bufs)
+	return n, s.closedError(err)
+}
+
+func (s *SrtSocket) readBatch(bufs [][]byte) (int, error) {
+	if len(bufs) == 0 {
+		return 0, nil
+	}
+	stride := len(bufs[0])
+	for _, b := range bufs[1:] {
+		if len(b) < stride {
+			stride = len(b)
+		}
+	}
+	if stride == 0 {
+		return 0, errors.New("srtgo: ReadBatch with an empty buffer")
+	}
+	bb := getBatchBuffer(stride*len(bufs), len(bufs))
+	defer batchBufferPool.Put(bb)
+
+	//Fastpath
+	if !s.blocking {
+		s.pd.reset(ModeRead)
+	}
+	n, err := srtRecvBatchImpl(s.socket, bb, stride, s.blocking)
+	for errors.Is(err, error(EAsyncRCV)) && !s.blocking {
+		if err = s.pd.wait(ModeRead); err != nil {
+			return 0, err
+		}
+		n, err = srtRecvBatchImpl(s.socket, bb, stride, s.blocking)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	for i := 0; i < n; i++ {
+		slot := bb.data[i*stride:]
+		bufs[i] = bufs[i][:copy(bufs[i], slot[:bb.lens[i]])]
+	}
+	return n, nil
+}
+
+// WriteBatch - Write each of bufs as a message to the SRT socket, sending as
+// many as the socket takes with a single cgo call, and waiting like Write when
+// the send buffer is full. The number of messages sent is returned, which is
+// len(bufs) unless an error occurs.
+// WriteBatch is meant for message mode, live or with messageapi.
+func (s *SrtSocket) WriteBatch(bufs [][]byte) (int, error) {
+	if s.isClosed() {
+		return 0, &SrtSocketClosed{}
+	}
+	n, err := s.writeBatch(bufs)
+	return n, s.closedError(err)
+}
+
+func (s *SrtSocket) writeBatch(bufs [][]byte) (int, error) {
+	if len(bufs) == 0 {
+		return 0, nil
+	}
+	size := 0
+	for _, b := range bufs {
+		if len(b) == 0 {
+			return 0, errors.New("srtgo: WriteBatch with an empty message")
+		}
+		size += len(b)
+	}
+	bb := getBatchBuffer(size, len(bufs))
+	defer batchBufferPool.Put(bb)
+	offset := 0
+	for i, b := range bufs {
+		offset += copy(bb.data[offset:], b)
+		bb.lens[i] = C.int(len(b))
+	}
+
+	//Fastpath
+	if !s.blocking {
+		s.pd.reset(ModeWrite)
+	}
+	sent := 0
+	offset = 0
+	for {
+		n, err := srtSendBatchImpl(s.socket, bb.data[offset:], bb.lens[sent:])
+		for _, l := range bb.lens[sent : sent+n] {
+			offset += int(l)
+		}
+		sent += n
+		if err == nil || !errors.Is(err, error(EAsyncSND)) || s.blocking {
+			return sent, err
+		}
+		if err := s.pd.wait(ModeWrite); err != nil {
+			return sent, err
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package srtgo
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+var batchOptions = map[string]string{"blocking": "0", "transtype": "file", "messageapi": "1"}
+
+func TestReadWriteBatch(t *testing.T) {
+	caller, remote := socketPair(t, batchOptions)
+
+	const count = 100
+	msgs := make([][]byte, count)
+	for i := range msgs {
+		msgs[i] = bytes.Repeat([]byte{byte(i)}, 100+i)
+	}
+	sent := make(chan error, 1)
+	go func() {
+		n, err := caller.WriteBatch(msgs)
+		if err == nil && n != count {
+			t.Errorf("sent %d messages, want %d", n, count)
+		}
+		sent <- err
+	}()
+
+	bufs := make([][]byte, 16)
+	for i := range bufs {
+		bufs[i] = make([]byte, 1500)
+	}
+	received := 0
+	for received < count {
+		//ReadBatch reslices the buffers to the messages
+		for i := range bufs {
+			bufs[i] = bufs[i][:cap(bufs[i])]
+		}
+		n, err := remote.ReadBatch(bufs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n == 0 {
+			t.Fatal("no message read")
+		}
+		for _, b := range bufs[:n] {
+			if !bytes.Equal(b, msgs[received]) {
+				t.Fatalf("message %d: got %d bytes of %d", received, len(b), b[0])
+			}
+			received++
+		}
+	}
+	if err := <-sent; err != nil {
+		t.Fatal(err)
+	}
+}
+
+var liveBatchOptions = map[string]string{"blocking": "0", "transtype": "live", "tlpktdrop": "0"}
+
+// benchmarkMessages - Send b.N messages of 1316 bytes, and read them, batch
+// at a time with ReadBatch and WriteBatch, or one by one with Read and Write
+// when batch is 1
+func benchmarkMessages(b *testing.B, options map[string]string, batch int) {
+	caller, remote := socketPair(b, options)
+
+	msgs := make([][]byte, batch)
+	for i := range msgs {
+		msgs[i] = make([]byte, 1316)
+	}
+	b.SetBytes(1316)
+	b.ResetTimer()
+
+	sent := make(chan error, 1)
+	go func() {
+		for n := 0; n < b.N; n += batch {
+			var err error
+			if batch == 1 {
+				_, err = caller.Write(msgs[0])
+			} else if b.N-n < batch {
+				_, err = caller.WriteBatch(msgs[:b.N-n])
+			} else {
+				_, err = caller.WriteBatch(msgs)
+			}
+			if err != nil {
+				sent <- err
+				return
+			}
+		}
+		sent <- nil
+	}()
+
+	bufs := make([][]byte, batch)
+	for i := range bufs {
+		bufs[i] = make([]byte, 1500)
+	}
+	for received := 0; received < b.N; {
+		//Fail rather than hang if a message is lost
+		remote.SetReadDeadline(time.Now().Add(5 * time.Second))
+		var n int
+		var err error
+		if batch == 1 {
+			_, err = remote.Read(bufs[0])
+			n = 1
+		} else {
+			for i := range bufs {
+				bufs[i] = bufs[i][:cap(bufs[i])]
+			}
+			n, err = remote.ReadBatch(bufs)
+		}
+		if err != nil {
+			b.Fatal(err)
+		}
+		received += n
+	}
+	if err := <-sent; err != nil {
+		b.Fatal(err)
+	}
+}
+
+func BenchmarkReadWrite(b *testing.B) {
+	benchmarkMessages(b, batchOptions, 1)
+}
+
+func BenchmarkReadWriteBatch16(b *testing.B) {
+	benchmarkMessages(b, batchOptions, 16)
+}
+
+func BenchmarkReadWriteBatch64(b *testing.B) {
+	benchmarkMessages(b, batchOptions, 64)
+}
+
+func BenchmarkReadWriteLive(b *testing.B) {
+	benchmarkMessages(b, liveBatchOptions, 1)
+}
+
+func BenchmarkReadWriteLiveBatch16(b *testing.B) {
+	benchmarkMessages(b, liveBatchOptions, 16)
+}
+
+func BenchmarkReadWriteLiveBatch64(b *testing.B) {
+	benchmarkMessages(b, liveBatchOptions, 64)
+}
@@ -0,0 +1,57 @@
+package srtgo
+
+import (
+	"net"
+	"strconv"
+	"testing"
+)
+
+func TestConnectBindLocalPort(t *testing.T) {
+	InitSRT()
+
+	port := randomPort()
+	localPort := port + 1
+	listener := NewSrtSocket("127.0.0.1", port, map[string]string{"blocking": "0", "transtype": "file"})
+	if listener == nil {
+		t.Fatal("Could not create a srt socket")
+	}
+	defer listener.Close()
+	if err := listener.Listen(1); err != nil {
+		t.Fatal(err)
+	}
+
+	caller := NewSrtSocket("127.0.0.1", port, map[string]string{
+		"blocking":  "0",
+		"transtype": "file",
+		"mode":      "caller",
+		"adapter":   "127.0.0.1",
+		"localport": strconv.Itoa(int(localPort)),
+	})
+	if caller == nil {
+		t.Fatal("Could not create a srt socket")
+	}
+	defer caller.Close()
+	if caller.Mode() != ModeCaller {
+		t.Fatalf("expected caller mode, got %d", caller.Mode())
+	}
+
+	go func() {
+		s, _, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer s.Close()
+	}()
+
+	if err := caller.Connect(); err != nil {
+		t.Fatal(err)
+	}
+
+	addr, err := caller.LocalAddr()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr.Port != int(localPort) || !addr.IP.Equal(net.IPv4(127, 0, 0, 1)) {
+		t.Errorf("caller bound to %s, expected 127.0.0.1:%d", addr, localPort)
+	}
+}
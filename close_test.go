@@ -0,0 +1,138 @@
+package srtgo
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// closeDuring - Run op in a goroutine, close s once op is blocked, and check
+// op returns a *SrtSocketClosed error
+func closeDuring(t *testing.T, s *SrtSocket, op func() error) {
+	t.Helper()
+	done := make(chan error, 1)
+	go func() {
+		done <- op()
+	}()
+	//Let op block
+	time.Sleep(100 * time.Millisecond)
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	select {
+	case err := <-done:
+		var closed *SrtSocketClosed
+		if !errors.As(err, &closed) {
+			t.Errorf("got %v, want *SrtSocketClosed", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("operation not interrupted by Close")
+	}
+}
+
+func TestCloseIdempotent(t *testing.T) {
+	s := NewSrtSocket("127.0.0.1", randomPort(), map[string]string{"blocking": "0", "mode": "listener"})
+	if s == nil {
+		t.Fatal("failed to create socket")
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("first Close: %v", err)
+	}
+	var closed *SrtSocketClosed
+	if err := s.Close(); !errors.As(err, &closed) {
+		t.Errorf("second Close returned %v", err)
+	}
+	if _, err := s.Read(make([]byte, 1316)); !errors.As(err, &closed) {
+		t.Errorf("Read after Close returned %v", err)
+	}
+	if _, err := s.Write(make([]byte, 1316)); !errors.As(err, &closed) {
+		t.Errorf("Write after Close returned %v", err)
+	}
+}
+
+func TestCloseConcurrent(t *testing.T) {
+	s := NewSrtSocket("127.0.0.1", randomPort(), map[string]string{"blocking": "0", "mode": "listener"})
+	if s == nil {
+		t.Fatal("failed to create socket")
+	}
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		successes int
+	)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if s.Close() == nil {
+				mu.Lock()
+				successes++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if successes != 1 {
+		t.Errorf("%d calls to Close succeeded, want 1", successes)
+	}
+}
+
+func testCloseDuringAccept(t *testing.T, blocking string) {
+	ln := NewSrtSocket("127.0.0.1", randomPort(), map[string]string{"blocking": blocking, "mode": "listener"})
+	if ln == nil {
+		t.Fatal("failed to create listener socket")
+	}
+	if err := ln.Listen(1); err != nil {
+		t.Fatal(err)
+	}
+	closeDuring(t, ln, func() error {
+		_, _, err := ln.Accept()
+		return err
+	})
+}
+
+func testCloseDuringRead(t *testing.T, blocking string) {
+	_, remote := socketPair(t, map[string]string{"blocking": blocking, "transtype": "file"})
+	closeDuring(t, remote, func() error {
+		_, err := remote.Read(make([]byte, 1316))
+		return err
+	})
+}
+
+func testCloseDuringWrite(t *testing.T, blocking string) {
+	//The remote never reads, so the writes block once the buffers are full
+	caller, _ := socketPair(t, map[string]string{"blocking": blocking, "transtype": "file", "sndbuf": "1000000", "rcvbuf": "1000000", "linger": "0"})
+	closeDuring(t, caller, func() error {
+		buf := make([]byte, 1316)
+		for {
+			if _, err := caller.Write(buf); err != nil {
+				return err
+			}
+		}
+	})
+}
+
+func TestCloseDuringAccept(t *testing.T) {
+	testCloseDuringAccept(t, "0")
+}
+
+func TestCloseDuringAcceptBlocking(t *testing.T) {
+	testCloseDuringAccept(t, "1")
+}
+
+func TestCloseDuringRead(t *testing.T) {
+	testCloseDuringRead(t, "0")
+}
+
+func TestCloseDuringReadBlocking(t *testing.T) {
+	testCloseDuringRead(t, "1")
+}
+
+func TestCloseDuringWrite(t *testing.T) {
+	testCloseDuringWrite(t, "0")
+}
+
+func TestCloseDuringWriteBlocking(t *testing.T) {
+	testCloseDuringWrite(t, "1")
+}
@@ -0,0 +1,418 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/haivision/srtgo"
+)
+
+// endpoint - Source or target of the transmission
+type endpoint interface {
+	io.ReadWriteCloser
+	// open (or reopen after a failure) the endpoint
+	open() error
+}
+
+// statsEndpoint - Endpoint with SRT statistics
+type statsEndpoint interface {
+	stats() (*srtgo.SrtStats, error)
+}
+
+// newEndpoint - Create the endpoint for a srt://, udp:// or file:// URI.
+// input tells whether data is read from the endpoint or written to it.
+func newEndpoint(uri string, input bool) (endpoint, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "srt":
+		host, port, options, err := srtgo.ParseSrtURL(uri)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := options["mode"]; !ok && host == "" {
+			options["mode"] = "listener"
+		}
+		if host == "" {
+			host = "0.0.0.0"
+		}
+		options["blocking"] = "1"
+		//Invalid values are fatal, rather than retried by auto-reconnect
+		if _, err := srtgo.ConfigFromOptions(options); err != nil {
+			return nil, fmt.Errorf("%s: %w", uri, err)
+		}
+		return &srtEndpoint{uri: uri, host: host, port: port, options: options}, nil
+	case "udp":
+		return newUDPEndpoint(u, input)
+	case "file":
+		path := u.Path
+		if u.Host != "" {
+			//file://con, like srt-live-transmit
+			path = u.Host + u.Path
+		}
+		if path == "" {
+			return nil, fmt.Errorf("missing path in %q", uri)
+		}
+		return &fileEndpoint{path: path, input: input}, nil
+	}
+	return nil, fmt.Errorf("unsupported scheme %q in %q", u.Scheme, uri)
+}
+
+// srtEndpoint - SRT caller, listener or rendezvous endpoint. A listener serves
+// one connection at a time.
+type srtEndpoint struct {
+	uri     string
+	host    string
+	port    uint16
+	options map[string]string
+
+	mu       sync.Mutex
+	listener *srtgo.SrtSocket
+	socket   *srtgo.SrtSocket
+	// Socket being connected by open, closed by Close to stop waiting
+	pending *srtgo.SrtSocket
+	closed  bool
+}
+
+func (e *srtEndpoint) open() error {
+	e.mu.Lock()
+	if e.closed {
+		e.mu.Unlock()
+		return errors.New(e.uri + ": closed")
+	}
+	if e.socket != nil {
+		e.socket.Close()
+		e.socket = nil
+	}
+	listener := e.listener
+	e.mu.Unlock()
+
+	var s *srtgo.SrtSocket
+	if mode := e.options["mode"]; mode == "listener" || mode == "server" {
+		if listener == nil {
+			var err error
+			listener, err = srtgo.NewSrtSocketE(e.host, e.port, e.options)
+			if err != nil {
+				return fmt.Errorf("%s: %w", e.uri, err)
+			}
+			//Stored before listening, so that Close interrupts Accept
+			if !e.track(&e.listener, listener) {
+				return errors.New(e.uri + ": closed")
+			}
+			if err := listener.Listen(1); err != nil {
+				e.mu.Lock()
+				e.listener = nil
+				e.mu.Unlock()
+				listener.Close()
+				return fmt.Errorf("%s: %w", e.uri, err)
+			}
+		}
+		conn, addr, err := listener.Accept()
+		if err != nil {
+			return fmt.Errorf("%s: %w", e.uri, err)
+		}
+		logf("%s: accepted connection from %s", e.uri, addr)
+		s = conn
+	} else {
+		var err error
+		s, err = srtgo.NewSrtSocketE(e.host, e.port, e.options)
+		if err != nil {
+			return fmt.Errorf("%s: %w", e.uri, err)
+		}
+		if !e.track(&e.pending, s) {
+			return errors.New(e.uri + ": closed")
+		}
+		err = s.Connect()
+		e.mu.Lock()
+		e.pending = nil
+		e.mu.Unlock()
+		if err != nil {
+			s.Close()
+			return fmt.Errorf("%s: %w", e.uri, err)
+		}
+		logf("%s: connected", e.uri)
+	}
+
+	if !e.track(&e.socket, s) {
+		return errors.New(e.uri + ": closed")
+	}
+	return nil
+}
+
+// track - Store s in *field for Close to find it, or close s and return false
+// when the endpoint was closed in the meantime
+func (e *srtEndpoint) track(field **srtgo.SrtSocket, s *srtgo.SrtSocket) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		s.Close()
+		return false
+	}
+	*field = s
+	return true
+}
+
+func (e *srtEndpoint) current() (*srtgo.SrtSocket, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.socket == nil {
+		return nil, errors.New(e.uri + ": not connected")
+	}
+	return e.socket, nil
+}
+
+func (e *srtEndpoint) Read(b []byte) (int, error) {
+	s, err := e.current()
+	if err != nil {
+		return 0, err
+	}
+	n, err := s.Read(b)
+	if err == nil && n == 0 {
+		return 0, io.EOF
+	}
+	return n, err
+}
+
+func (e *srtEndpoint) Write(b []byte) (int, error) {
+	s, err := e.current()
+	if err != nil {
+		return 0, err
+	}
+	return s.Write(b)
+}
+
+func (e *srtEndpoint) stats() (*srtgo.SrtStats, error) {
+	s, err := e.current()
+	if err != nil {
+		return nil, err
+	}
+	return s.Stats()
+}
+
+func (e *srtEndpoint) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.closed = true
+	if e.pending != nil {
+		e.pending.Close()
+		e.pending = nil
+	}
+	if e.socket != nil {
+		e.socket.Close()
+		e.socket = nil
+	}
+	if e.listener != nil {
+		e.listener.Close()
+		e.listener = nil
+	}
+	return nil
+}
+
+// udpEndpoint - UDP unicast or multicast endpoint. As an input, it listens on
+// the port of the URI (joining the group for a multicast address), as an
+// output it sends to the address of the URI.
+type udpEndpoint struct {
+	addr  *net.UDPAddr
+	input bool
+
+	mu     sync.Mutex
+	conn   *net.UDPConn
+	closed bool
+}
+
+func newUDPEndpoint(u *url.URL, input bool) (endpoint, error) {
+	//In udp://@:port, like srt-live-transmit takes, "@" ends an empty
+	//userinfo and the host is already empty
+	host := u.Hostname()
+	port, err := strconv.ParseUint(u.Port(), 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("invalid port in %q", u.String())
+	}
+	addr := &net.UDPAddr{Port: int(port)}
+	if host != "" {
+		ip, err := net.ResolveIPAddr("ip", host)
+		if err != nil {
+			return nil, err
+		}
+		addr.IP = ip.IP
+	} else if !input {
+		return nil, fmt.Errorf("missing host in output %q", u.String())
+	}
+	return &udpEndpoint{addr: addr, input: input}, nil
+}
+
+func (e *udpEndpoint) open() error {
+	e.mu.Lock()
+	if e.closed {
+		e.mu.Unlock()
+		return errors.New(e.addr.String() + ": closed")
+	}
+	opened := e.conn != nil
+	e.mu.Unlock()
+	if opened {
+		return nil
+	}
+
+	var conn *net.UDPConn
+	var err error
+	switch {
+	case !e.input:
+		conn, err = net.DialUDP("udp", nil, e.addr)
+	case e.addr.IP != nil && e.addr.IP.IsMulticast():
+		conn, err = net.ListenMulticastUDP("udp", nil, e.addr)
+	default:
+		conn, err = net.ListenUDP("udp", e.addr)
+	}
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		conn.Close()
+		return errors.New(e.addr.String() + ": closed")
+	}
+	e.conn = conn
+	return nil
+}
+
+func (e *udpEndpoint) current() (*net.UDPConn, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.conn == nil {
+		return nil, errors.New(e.addr.String() + ": not open")
+	}
+	return e.conn, nil
+}
+
+func (e *udpEndpoint) Read(b []byte) (int, error) {
+	conn, err := e.current()
+	if err != nil {
+		return 0, err
+	}
+	n, _, err := conn.ReadFromUDP(b)
+	return n, err
+}
+
+func (e *udpEndpoint) Write(b []byte) (int, error) {
+	conn, err := e.current()
+	if err != nil {
+		return 0, err
+	}
+	return conn.Write(b)
+}
+
+func (e *udpEndpoint) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.closed = true
+	if e.conn == nil {
+		return nil
+	}
+	err := e.conn.Close()
+	e.conn = nil
+	return err
+}
+
+// fileEndpoint - File, or standard input/output for "con" and "-"
+type fileEndpoint struct {
+	path  string
+	input bool
+
+	mu     sync.Mutex
+	f      *os.File
+	closed bool
+}
+
+func (e *fileEndpoint) open() error {
+	e.mu.Lock()
+	if e.closed {
+		e.mu.Unlock()
+		return errors.New(e.path + ": closed")
+	}
+	opened := e.f != nil
+	e.mu.Unlock()
+	if opened {
+		return nil
+	}
+
+	var f *os.File
+	var err error
+	switch {
+	case e.path == "con" || e.path == "-":
+		f = os.Stdout
+		if e.input {
+			f = os.Stdin
+		}
+	case e.input:
+		f, err = os.Open(e.path)
+	default:
+		f, err = os.Create(e.path)
+	}
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		closeFile(f)
+		return errors.New(e.path + ": closed")
+	}
+	e.f = f
+	return nil
+}
+
+func (e *fileEndpoint) current() (*os.File, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.f == nil {
+		return nil, errors.New(e.path + ": not open")
+	}
+	return e.f, nil
+}
+
+func (e *fileEndpoint) Read(b []byte) (int, error) {
+	f, err := e.current()
+	if err != nil {
+		return 0, err
+	}
+	return f.Read(b)
+}
+
+func (e *fileEndpoint) Write(b []byte) (int, error) {
+	f, err := e.current()
+	if err != nil {
+		return 0, err
+	}
+	return f.Write(b)
+}
+
+func (e *fileEndpoint) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.closed = true
+	if e.f == nil {
+		return nil
+	}
+	err := closeFile(e.f)
+	e.f = nil
+	return err
+}
+
+// closeFile - Close f, unless it is the standard input or output
+func closeFile(f *os.File) error {
+	if f == os.Stdin || f == os.Stdout {
+		return nil
+	}
+	return f.Close()
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"net"
+	"testing"
+)
+
+func TestNewEndpoint(t *testing.T) {
+	e, err := newEndpoint("srt://:9000?latency=200", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	se, ok := e.(*srtEndpoint)
+	if !ok {
+		t.Fatalf("srt:// gave a %T", e)
+	}
+	if se.host != "0.0.0.0" || se.port != 9000 || se.options["mode"] != "listener" || se.options["latency"] != "200" {
+		t.Errorf("unexpected SRT endpoint %+v", se)
+	}
+
+	e, err = newEndpoint("srt://127.0.0.1:9000?mode=rendezvous", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if se := e.(*srtEndpoint); se.host != "127.0.0.1" || se.options["mode"] != "rendezvous" {
+		t.Errorf("unexpected SRT endpoint %+v", se)
+	}
+
+	e, err = newEndpoint("udp://@:5000", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ue := e.(*udpEndpoint); ue.addr.IP != nil || ue.addr.Port != 5000 {
+		t.Errorf("unexpected UDP endpoint %+v", ue.addr)
+	}
+
+	e, err = newEndpoint("udp://239.0.0.1:5000", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ue := e.(*udpEndpoint); !ue.addr.IP.Equal(net.IPv4(239, 0, 0, 1)) {
+		t.Errorf("unexpected UDP endpoint %+v", ue.addr)
+	}
+
+	e, err = newEndpoint("file://con", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fe := e.(*fileEndpoint); fe.path != "con" {
+		t.Errorf("unexpected file endpoint %+v", fe)
+	}
+
+	e, err = newEndpoint("file:///tmp/out.ts", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fe := e.(*fileEndpoint); fe.path != "/tmp/out.ts" {
+		t.Errorf("unexpected file endpoint %+v", fe)
+	}
+}
+
+func TestNewEndpointErrors(t *testing.T) {
+	for _, uri := range []string{
+		"rtmp://host:1935",
+		"udp://:5000",
+		"udp://host",
+		"srt://host",
+		"srt://host:9000?unknown=1",
+		"srt://host:9000?latency=abc",
+		"srt://host:9000?mode=sideways",
+		"file://",
+	} {
+		if _, err := newEndpoint(uri, false); err == nil {
+			t.Errorf("%s: expected an error", uri)
+		}
+	}
+}
+
+func TestSrtEndpointClosed(t *testing.T) {
+	e, err := newEndpoint("srt://:9000", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	//A signal may close the endpoint before it is opened
+	e.Close()
+	if err := e.open(); err == nil {
+		t.Error("opened a closed endpoint")
+	}
+	if se := e.(*srtEndpoint); se.listener != nil || se.socket != nil {
+		t.Errorf("sockets left after Close: %+v", se)
+	}
+}
+
+func TestEndpointsClosed(t *testing.T) {
+	for _, uri := range []string{"udp://127.0.0.1:5000", "file://" + t.TempDir() + "/out.ts"} {
+		e, err := newEndpoint(uri, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		e.Close()
+		if err := e.open(); err == nil {
+			t.Errorf("%s: opened a closed endpoint", uri)
+		}
+		if _, err := e.Write([]byte("data")); err == nil {
+			t.Errorf("%s: wrote to a closed endpoint", uri)
+		}
+	}
+}
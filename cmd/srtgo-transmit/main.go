@@ -0,0 +1,231 @@
+// Command srtgo-transmit moves data between srt://, udp:// and file:// URIs,
+// like srt-live-transmit:
+//
+//	srtgo-transmit [flags] <input-uri> <output-uri>
+//
+// SRT URIs take the options of srtgo.ParseSrtURL, and select the caller,
+// listener or rendezvous mode with the "mode" option (listener by default when
+// the host is empty, as in srt://:9000). UDP inputs listen on the port of the
+// URI and join the group of multicast addresses (udp://@:5000,
+// udp://239.0.0.1:5000); UDP outputs send to the address of the URI.
+// file://con and file://- are the standard input or output.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/haivision/srtgo"
+)
+
+var verbose bool
+
+func logf(format string, args ...interface{}) {
+	if verbose {
+		log.Printf(format, args...)
+	}
+}
+
+// config - Command line settings
+type config struct {
+	input, output string
+	chunkSize     int
+	statsInterval time.Duration
+	statsFormat   string
+	statsOut      string
+	autoReconnect bool
+	srtLogLevel   int
+	srtLogFA      string
+}
+
+func main() {
+	var c config
+	flag.IntVar(&c.chunkSize, "chunk", 1316, "size of the chunks read from the input")
+	flag.DurationVar(&c.statsInterval, "stats", 0, "interval of the SRT statistics reports, 0 to disable")
+	flag.StringVar(&c.statsFormat, "statsformat", "json", "format of the statistics reports: json or csv")
+	flag.StringVar(&c.statsOut, "statsout", "", "file to write the statistics reports to, standard error by default")
+	flag.BoolVar(&c.autoReconnect, "autoreconnect", false, "reopen the SRT endpoints when their connection breaks")
+	flag.IntVar(&c.srtLogLevel, "loglevel", int(srtgo.SrtLogLevelErr), "libsrt log level, a syslog severity from 2 (critical) to 7 (debug)")
+	flag.StringVar(&c.srtLogFA, "logfa", "", "comma-separated libsrt log functional areas to enable only, e.g. conn,congest (libsrt 1.5 or newer)")
+	flag.BoolVar(&verbose, "v", false, "log connections and reconnections")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] <input-uri> <output-uri>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	c.input, c.output = flag.Arg(0), flag.Arg(1)
+
+	if err := run(c); err != nil {
+		log.Print(err)
+		os.Exit(1)
+	}
+}
+
+// run - Transmit as configured by c. Errors are returned rather than fatal, so
+// that the deferred cleanups always run.
+func run(c config) error {
+	srtgo.InitSRT()
+	defer srtgo.CleanupSRT()
+	srtgo.SrtSetLogLevel(srtgo.SrtLogLevel(c.srtLogLevel))
+	if c.srtLogFA != "" {
+		fas, err := srtgo.ParseSrtLogFA(c.srtLogFA)
+		if err != nil {
+			return err
+		}
+		if err := srtgo.SrtResetLogFA(fas...); err != nil {
+			return err
+		}
+	}
+
+	input, err := newEndpoint(c.input, true)
+	if err != nil {
+		return err
+	}
+	output, err := newEndpoint(c.output, false)
+	if err != nil {
+		return err
+	}
+
+	var reporter *statsReporter
+	if c.statsInterval > 0 {
+		w := io.Writer(os.Stderr)
+		if c.statsOut != "" {
+			f, err := os.Create(c.statsOut)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			w = f
+		}
+		reporter, err = newStatsReporter(w, c.statsFormat, map[string]endpoint{"input": input, "output": output})
+		if err != nil {
+			return err
+		}
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	stopping := make(chan struct{})
+	go func() {
+		<-signals
+		close(stopping)
+		input.Close()
+		output.Close()
+	}()
+
+	if reporter != nil {
+		go reporter.run(c.statsInterval, stopping)
+	}
+
+	t := &transmitter{
+		input:         input,
+		output:        output,
+		chunkSize:     c.chunkSize,
+		autoReconnect: c.autoReconnect,
+		stopping:      stopping,
+	}
+	err = t.run()
+	input.Close()
+	output.Close()
+	return err
+}
+
+type transmitter struct {
+	input, output endpoint
+	chunkSize     int
+	autoReconnect bool
+	stopping      chan struct{}
+}
+
+// reconnectDelay is the time between two attempts to reopen an endpoint
+const reconnectDelay = time.Second
+
+// run - Copy the input to the output until the input ends, or until an
+// endpoint fails and cannot be reopened
+func (t *transmitter) run() error {
+	if err := t.open(t.input); err != nil {
+		return err
+	}
+	if err := t.open(t.output); err != nil {
+		return err
+	}
+
+	buf := make([]byte, t.chunkSize)
+	for !t.isStopping() {
+		n, err := t.input.Read(buf)
+		if err != nil {
+			//End of the file, or the SRT peer disconnected
+			if errors.Is(err, io.EOF) || errors.Is(err, srtgo.EConnLost) {
+				if !isSRT(t.input) || !t.autoReconnect {
+					return nil
+				}
+			}
+			if err = t.recover(t.input, err); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, err := t.output.Write(buf[:n]); err != nil {
+			if err = t.recover(t.output, err); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// open - Open an endpoint, retrying SRT endpoints with auto-reconnect
+func (t *transmitter) open(e endpoint) error {
+	if err := e.open(); err != nil {
+		return t.recover(e, err)
+	}
+	return nil
+}
+
+// recover - Reopen an SRT endpoint that failed with err, when auto-reconnect
+// is enabled, or return err
+func (t *transmitter) recover(e endpoint, err error) error {
+	if t.isStopping() {
+		return nil
+	}
+	if !t.autoReconnect || !isSRT(e) {
+		return err
+	}
+	for {
+		logf("%v, reconnecting", err)
+		select {
+		case <-t.stopping:
+			return nil
+		case <-time.After(reconnectDelay):
+		}
+		if err = e.open(); err == nil {
+			return nil
+		}
+	}
+}
+
+func (t *transmitter) isStopping() bool {
+	select {
+	case <-t.stopping:
+		return true
+	default:
+		return false
+	}
+}
+
+func isSRT(e endpoint) bool {
+	_, ok := e.(*srtEndpoint)
+	return ok
+}
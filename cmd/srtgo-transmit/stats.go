@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/haivision/srtgo"
+)
+
+// statsReporter - Periodic report of the statistics of the SRT endpoints
+type statsReporter struct {
+	w         io.Writer
+	format    string
+	endpoints map[string]statsEndpoint
+	names     []string
+	csv       *csv.Writer
+}
+
+// jsonReport - Line of the JSON report
+type jsonReport struct {
+	Time     time.Time       `json:"time"`
+	Endpoint string          `json:"endpoint"`
+	Stats    *srtgo.SrtStats `json:"stats"`
+}
+
+func newStatsReporter(w io.Writer, format string, endpoints map[string]endpoint) (*statsReporter, error) {
+	r := &statsReporter{w: w, format: format, endpoints: make(map[string]statsEndpoint)}
+	for name, e := range endpoints {
+		if se, ok := e.(statsEndpoint); ok {
+			r.endpoints[name] = se
+			r.names = append(r.names, name)
+		}
+	}
+	sort.Strings(r.names)
+
+	switch format {
+	case "json":
+	case "csv":
+		r.csv = csv.NewWriter(w)
+		header := []string{"Time", "Endpoint"}
+		t := reflect.TypeOf(srtgo.SrtStats{})
+		for i := 0; i < t.NumField(); i++ {
+			header = append(header, t.Field(i).Name)
+		}
+		if err := r.write(header); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown stats format %q, expected json or csv", format)
+	}
+	return r, nil
+}
+
+func (r *statsReporter) run(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			for _, name := range r.names {
+				stats, err := r.endpoints[name].stats()
+				if err != nil {
+					//Not connected yet, or reconnecting
+					continue
+				}
+				if err := r.report(now, name, stats); err != nil {
+					log.Printf("writing stats: %v", err)
+				}
+			}
+		}
+	}
+}
+
+func (r *statsReporter) report(now time.Time, name string, stats *srtgo.SrtStats) error {
+	if r.csv == nil {
+		line, err := json.Marshal(jsonReport{now, name, stats})
+		if err != nil {
+			return err
+		}
+		_, err = r.w.Write(append(line, '\n'))
+		return err
+	}
+
+	record := []string{now.Format(time.RFC3339Nano), name}
+	v := reflect.ValueOf(stats).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		switch f := v.Field(i); f.Kind() {
+		case reflect.Float64:
+			record = append(record, strconv.FormatFloat(f.Float(), 'f', -1, 64))
+		default:
+			record = append(record, strconv.FormatInt(f.Int(), 10))
+		}
+	}
+	return r.write(record)
+}
+
+func (r *statsReporter) write(record []string) error {
+	if err := r.csv.Write(record); err != nil {
+		return err
+	}
+	r.csv.Flush()
+	return r.csv.Error()
+}
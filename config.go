@@ -0,0 +1,538 @@
+package srtgo
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TransType - SRT transmission type
+type TransType int
+
+// Transmission types. The zero value keeps the libsrt default (live).
+const (
+	TransTypeLive TransType = iota + 1
+	TransTypeFile
+)
+
+func (t TransType) String() string {
+	switch t {
+	case TransTypeLive:
+		return "live"
+	case TransTypeFile:
+		return "file"
+	}
+	return "TransType(" + strconv.Itoa(int(t)) + ")"
+}
+
+// maxStreamIDLen is the maximum length of SRTO_STREAMID
+const maxStreamIDLen = 512
+
+// maxLivePayloadSize is the maximum payload size in live mode (SRT_LIVE_MAX_PLSIZE)
+const maxLivePayloadSize = 1456
+
+// Config - Typed configuration of an SRT socket, the counterpart of the
+// options map accepted by NewSrtSocket.
+//
+// Fields left at their zero value keep the libsrt default. Options that are
+// on by default in libsrt, and numeric options for which zero is a meaningful
+// value other than the default (latencies, MaxBW, IPTOS), are pointers so they
+// can be set to false or zero explicitly: use Bool, Int, Int64 and Duration to
+// set them. Durations are applied with millisecond precision, except Linger
+// which uses seconds.
+type Config struct {
+	// Working mode: ModeCaller, ModeListener or ModeRendezvouz. Zero selects
+	// the mode from the host and Adapter, like NewSrtSocket does.
+	Mode int
+	// Use blocking SRT calls instead of the internal poll server
+	Blocking bool
+	// Time to wait for unsent data when closing (SRTO_LINGER)
+	Linger time.Duration
+	// Size of the packets read and written by the application
+	PacketSize int
+	// Local address to bind to before connecting, in caller and rendezvous mode
+	Adapter string
+	// Local port to bind to before connecting, in caller and rendezvous mode.
+	// Defaults to any port for callers and to the remote port in rendezvous mode.
+	LocalPort uint16
+
+	TransType          TransType      // SRTO_TRANSTYPE
+	MaxBW              *int64         // SRTO_MAXBW
+	PBKeyLen           int            // SRTO_PBKEYLEN
+	Passphrase         string         // SRTO_PASSPHRASE
+	MSS                int            // SRTO_MSS
+	FC                 int            // SRTO_FC
+	SndBuf             int            // SRTO_SNDBUF
+	RcvBuf             int            // SRTO_RCVBUF
+	IPTTL              int            // SRTO_IPTTL
+	IPTOS              *int           // SRTO_IPTOS
+	InputBW            int64          // SRTO_INPUTBW
+	OHeadBW            int            // SRTO_OHEADBW
+	Latency            *time.Duration // SRTO_LATENCY
+	TSBPDMode          *bool          // SRTO_TSBPDMODE
+	TLPktDrop          *bool          // SRTO_TLPKTDROP
+	SndDropDelay       time.Duration  // SRTO_SNDDROPDELAY, negative disables the extra delay
+	NAKReport          *bool          // SRTO_NAKREPORT
+	ConnTimeout        time.Duration  // SRTO_CONNTIMEO
+	LossMaxTTL         int            // SRTO_LOSSMAXTTL
+	RcvLatency         *time.Duration // SRTO_RCVLATENCY
+	PeerLatency        *time.Duration // SRTO_PEERLATENCY
+	MinVersion         int            // SRTO_MINVERSION
+	StreamID           string         // SRTO_STREAMID
+	Congestion         string         // SRTO_CONGESTION
+	MessageAPI         *bool          // SRTO_MESSAGEAPI
+	PayloadSize        int            // SRTO_PAYLOADSIZE
+	KMRefreshRate      int            // SRTO_KMREFRESHRATE
+	KMPreAnnounce      int            // SRTO_KMPREANNOUNCE
+	EnforcedEncryption *bool          // SRTO_ENFORCEDENCRYPTION
+	PeerIdleTimeout    time.Duration  // SRTO_PEERIDLETIMEO
+	PacketFilter       string         // SRTO_PACKETFILTER
+
+	UDPSndBuf             int           // SRTO_UDP_SNDBUF
+	UDPRcvBuf             int           // SRTO_UDP_RCVBUF
+	IPv6Only              *bool         // SRTO_IPV6ONLY
+	BindToDevice          string        // SRTO_BINDTODEVICE, libsrt 1.4.2 or newer
+	RetransmitAlgo        int           // SRTO_RETRANSMITALGO, libsrt 1.4.2 or newer
+	DriftTracer           *bool         // SRTO_DRIFTTRACER, libsrt 1.4.2 or newer
+	MinInputBW            int64         // SRTO_MININPUTBW, libsrt 1.4.3 or newer
+	GroupConnect          bool          // SRTO_GROUPCONNECT, libsrt 1.5.0 or newer
+	GroupMinStableTimeout time.Duration // SRTO_GROUPMINSTABLETIMEO, libsrt 1.5.0 or newer
+	CryptoMode            int           // SRTO_CRYPTOMODE, libsrt 1.5.2 or newer
+
+	// Options given explicitly in the map the Config was built from, so an
+	// explicit zero value ("latency=0") is still applied
+	explicit map[string]bool
+}
+
+// Bool - Return a pointer to b, for the optional boolean fields of Config
+func Bool(b bool) *bool {
+	return &b
+}
+
+// Int - Return a pointer to i, for the optional int fields of Config
+func Int(i int) *int {
+	return &i
+}
+
+// Int64 - Return a pointer to i, for the optional int64 fields of Config
+func Int64(i int64) *int64 {
+	return &i
+}
+
+// Duration - Return a pointer to d, for the optional duration fields of Config
+func Duration(d time.Duration) *time.Duration {
+	return &d
+}
+
+// OptionError - An invalid option value
+type OptionError struct {
+	Option string
+	Value  string
+	Err    error
+}
+
+func (e *OptionError) Error() string {
+	return fmt.Sprintf("invalid value %q for option %s: %v", e.Value, e.Option, e.Err)
+}
+
+func (e *OptionError) Unwrap() error {
+	return e.Err
+}
+
+// ConfigError - Every invalid option found in a configuration
+type ConfigError []*OptionError
+
+func (e ConfigError) Error() string {
+	msgs := make([]string, len(e))
+	for i, oe := range e {
+		msgs[i] = oe.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (e *ConfigError) add(option, value string, err error) {
+	*e = append(*e, &OptionError{Option: option, Value: value, Err: err})
+}
+
+func (e ConfigError) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// ConfigFromOptions - Convert an options map, as accepted by NewSrtSocket,
+// into a Config. Every unknown option and every value that cannot be parsed
+// is reported in the returned ConfigError; the returned Config holds all the
+// options that could be converted.
+func ConfigFromOptions(options map[string]string) (Config, error) {
+	var c Config
+	var errs ConfigError
+	c.explicit = make(map[string]bool, len(options))
+
+	for name, val := range options {
+		var err error
+		switch name {
+		case "mode":
+			switch val {
+			case "client", "caller":
+				c.Mode = ModeCaller
+			case "server", "listener":
+				c.Mode = ModeListener
+			case "rendezvous":
+				c.Mode = ModeRendezvouz
+			case "default":
+				c.Mode = 0
+			default:
+				err = fmt.Errorf("unknown mode")
+			}
+		case "blocking":
+			//Anything but "0" has always enabled blocking mode
+			c.Blocking = val != "0"
+			_, err = strconv.ParseBool(val)
+		case "linger":
+			c.Linger, err = parseDuration(val, time.Second)
+		case "pktsize":
+			c.PacketSize, err = strconv.Atoi(val)
+		case "adapter":
+			c.Adapter = val
+		case "localport":
+			var port uint64
+			port, err = strconv.ParseUint(val, 10, 16)
+			c.LocalPort = uint16(port)
+		case "transtype":
+			switch val {
+			case "live":
+				c.TransType = TransTypeLive
+			case "file":
+				c.TransType = TransTypeFile
+			default:
+				err = fmt.Errorf("expected live or file")
+			}
+		case "maxbw":
+			c.MaxBW, err = parseInt64(val)
+		case "pbkeylen":
+			c.PBKeyLen, err = strconv.Atoi(val)
+		case "passphrase":
+			c.Passphrase = val
+		case "mss":
+			c.MSS, err = strconv.Atoi(val)
+		case "fc":
+			c.FC, err = strconv.Atoi(val)
+		case "sndbuf":
+			c.SndBuf, err = strconv.Atoi(val)
+		case "rcvbuf":
+			c.RcvBuf, err = strconv.Atoi(val)
+		case "ipttl":
+			c.IPTTL, err = strconv.Atoi(val)
+		case "iptos":
+			c.IPTOS, err = parseInt(val)
+		case "inputbw":
+			c.InputBW, err = strconv.ParseInt(val, 10, 64)
+		case "oheadbw":
+			c.OHeadBW, err = strconv.Atoi(val)
+		case "latency":
+			c.Latency, err = parseOptionalDuration(val, time.Millisecond)
+		case "tsbpdmode":
+			c.TSBPDMode, err = parseBool(val)
+		case "tlpktdrop":
+			c.TLPktDrop, err = parseBool(val)
+		case "snddropdelay":
+			c.SndDropDelay, err = parseDuration(val, time.Millisecond)
+		case "nakreport":
+			c.NAKReport, err = parseBool(val)
+		case "conntimeo":
+			c.ConnTimeout, err = parseDuration(val, time.Millisecond)
+		case "lossmaxttl":
+			c.LossMaxTTL, err = strconv.Atoi(val)
+		case "rcvlatency":
+			c.RcvLatency, err = parseOptionalDuration(val, time.Millisecond)
+		case "peerlatency":
+			c.PeerLatency, err = parseOptionalDuration(val, time.Millisecond)
+		case "minversion":
+			c.MinVersion, err = strconv.Atoi(val)
+		case "streamid":
+			c.StreamID = val
+		case "congestion":
+			c.Congestion = val
+		case "messageapi":
+			c.MessageAPI, err = parseBool(val)
+		case "payloadsize":
+			c.PayloadSize, err = strconv.Atoi(val)
+		case "kmrefreshrate":
+			c.KMRefreshRate, err = strconv.Atoi(val)
+		case "kmpreannounce":
+			c.KMPreAnnounce, err = strconv.Atoi(val)
+		case "enforcedencryption":
+			c.EnforcedEncryption, err = parseBool(val)
+		case "peeridletimeo":
+			c.PeerIdleTimeout, err = parseDuration(val, time.Millisecond)
+		case "packetfilter":
+			c.PacketFilter = val
+		case "udp_sndbuf":
+			c.UDPSndBuf, err = strconv.Atoi(val)
+		case "udp_rcvbuf":
+			c.UDPRcvBuf, err = strconv.Atoi(val)
+		case "ipv6only":
+			c.IPv6Only, err = parseBool(val)
+		case "bindtodevice":
+			c.BindToDevice = val
+		case "retransmitalgo":
+			c.RetransmitAlgo, err = strconv.Atoi(val)
+		case "drifttracer":
+			c.DriftTracer, err = parseBool(val)
+		case "mininputbw":
+			c.MinInputBW, err = strconv.ParseInt(val, 10, 64)
+		case "groupconnect":
+			var b *bool
+			if b, err = parseBool(val); err == nil {
+				c.GroupConnect = *b
+			}
+		case "groupminstabletimeo":
+			c.GroupMinStableTimeout, err = parseDuration(val, time.Millisecond)
+		case "cryptomode":
+			c.CryptoMode, err = strconv.Atoi(val)
+		default:
+			err = fmt.Errorf("unknown option")
+		}
+		if err != nil {
+			errs.add(name, val, err)
+			continue
+		}
+		c.explicit[name] = true
+	}
+
+	return c, errs.err()
+}
+
+// parseDuration - Parse an integer amount of unit
+func parseDuration(val string, unit time.Duration) (time.Duration, error) {
+	v, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("expected an integer number of %s", unitName(unit))
+	}
+	return time.Duration(v) * unit, nil
+}
+
+// parseOptionalDuration - Parse an integer amount of unit, for the optional
+// duration fields of Config
+func parseOptionalDuration(val string, unit time.Duration) (*time.Duration, error) {
+	d, err := parseDuration(val, unit)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// parseInt - Parse an integer, for the optional int fields of Config
+func parseInt(val string) (*int, error) {
+	i, err := strconv.Atoi(val)
+	if err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+// parseInt64 - Parse an integer, for the optional int64 fields of Config
+func parseInt64(val string) (*int64, error) {
+	i, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+func unitName(unit time.Duration) string {
+	if unit == time.Second {
+		return "seconds"
+	}
+	return "milliseconds"
+}
+
+// parseBool - Parse the "0"/"1" boolean values used by the options map
+func parseBool(val string) (*bool, error) {
+	switch val {
+	case "1":
+		return Bool(true), nil
+	case "0":
+		return Bool(false), nil
+	}
+	return nil, fmt.Errorf("expected 0 or 1")
+}
+
+// Validate - Check every field of the Config and report all invalid ones
+// in a ConfigError
+func (c Config) Validate() error {
+	var errs ConfigError
+	check := func(ok bool, option string, value interface{}, format string, args ...interface{}) {
+		if !ok {
+			errs.add(option, fmt.Sprint(value), fmt.Errorf(format, args...))
+		}
+	}
+	nonNegative := func(option string, value int64) {
+		check(value >= 0, option, value, "must not be negative")
+	}
+	nonNegativeDuration := func(option string, d *time.Duration) {
+		if d != nil {
+			nonNegative(option, int64(*d))
+		}
+	}
+
+	check(c.Mode == 0 || c.Mode == ModeCaller || c.Mode == ModeListener || c.Mode == ModeRendezvouz,
+		"mode", c.Mode, "unknown mode")
+	nonNegative("linger", int64(c.Linger))
+	nonNegative("pktsize", int64(c.PacketSize))
+	check(c.Adapter == "" || net.ParseIP(c.Adapter) != nil, "adapter", c.Adapter, "not an IP address")
+
+	check(c.TransType == 0 || c.TransType == TransTypeLive || c.TransType == TransTypeFile,
+		"transtype", c.TransType, "expected live or file")
+	if c.MaxBW != nil {
+		check(*c.MaxBW >= -1, "maxbw", *c.MaxBW, "must be -1 (unlimited) or more")
+	}
+	check(c.PBKeyLen == 0 || c.PBKeyLen == 16 || c.PBKeyLen == 24 || c.PBKeyLen == 32,
+		"pbkeylen", c.PBKeyLen, "expected 16, 24 or 32")
+	check(c.Passphrase == "" || len(c.Passphrase) >= 10 && len(c.Passphrase) <= 79,
+		"passphrase", strings.Repeat("*", len(c.Passphrase)), "must be 10 to 79 characters long")
+	check(c.MSS == 0 || c.MSS >= 76, "mss", c.MSS, "must be at least 76")
+	check(c.FC == 0 || c.FC >= 32, "fc", c.FC, "must be at least 32")
+	nonNegative("sndbuf", int64(c.SndBuf))
+	nonNegative("rcvbuf", int64(c.RcvBuf))
+	//A zero IPTTL is unset and left to libsrt, unless given explicitly
+	check(c.IPTTL == 0 && !c.explicit["ipttl"] || c.IPTTL >= 1 && c.IPTTL <= 255,
+		"ipttl", c.IPTTL, "must be between 1 and 255")
+	if c.IPTOS != nil {
+		check(*c.IPTOS >= 0 && *c.IPTOS <= 255, "iptos", *c.IPTOS, "must be between 0 and 255")
+	}
+	nonNegative("inputbw", c.InputBW)
+	check(c.OHeadBW == 0 || c.OHeadBW >= 5 && c.OHeadBW <= 100, "oheadbw", c.OHeadBW, "must be between 5 and 100")
+	nonNegativeDuration("latency", c.Latency)
+	check(c.SndDropDelay >= -time.Millisecond, "snddropdelay", c.SndDropDelay, "must be -1ms or more")
+	nonNegative("conntimeo", int64(c.ConnTimeout))
+	nonNegative("lossmaxttl", int64(c.LossMaxTTL))
+	nonNegativeDuration("rcvlatency", c.RcvLatency)
+	nonNegativeDuration("peerlatency", c.PeerLatency)
+	nonNegative("minversion", int64(c.MinVersion))
+	check(len(c.StreamID) <= maxStreamIDLen, "streamid", c.StreamID, "longer than %d characters", maxStreamIDLen)
+	check(c.Congestion == "" || c.Congestion == "live" || c.Congestion == "file",
+		"congestion", c.Congestion, "expected live or file")
+	check(c.PayloadSize >= 0 && (c.TransType == TransTypeFile || c.PayloadSize <= maxLivePayloadSize),
+		"payloadsize", c.PayloadSize, "must be between 0 and %d in live mode", maxLivePayloadSize)
+	nonNegative("kmrefreshrate", int64(c.KMRefreshRate))
+	nonNegative("kmpreannounce", int64(c.KMPreAnnounce))
+	nonNegative("peeridletimeo", int64(c.PeerIdleTimeout))
+	nonNegative("udp_sndbuf", int64(c.UDPSndBuf))
+	nonNegative("udp_rcvbuf", int64(c.UDPRcvBuf))
+	check(c.RetransmitAlgo == 0 || c.RetransmitAlgo == 1, "retransmitalgo", c.RetransmitAlgo, "expected 0 or 1")
+	nonNegative("mininputbw", c.MinInputBW)
+	nonNegative("groupminstabletimeo", int64(c.GroupMinStableTimeout))
+	check(c.CryptoMode >= 0 && c.CryptoMode <= 2, "cryptomode", c.CryptoMode, "expected 0 (auto), 1 (AES-CTR) or 2 (AES-GCM)")
+
+	return errs.err()
+}
+
+// options - Render the Config as an options map, with only the options
+// that are set
+func (c Config) options() map[string]string {
+	m := make(map[string]string)
+	set := func(name string, isZero bool, val string) {
+		if !isZero || c.explicit[name] {
+			m[name] = val
+		}
+	}
+	setInt := func(name string, v int64) {
+		set(name, v == 0, strconv.FormatInt(v, 10))
+	}
+	setDuration := func(name string, d, unit time.Duration) {
+		set(name, d == 0, strconv.FormatInt(int64(d/unit), 10))
+	}
+	setOptionalInt := func(name string, v *int64) {
+		if v != nil {
+			m[name] = strconv.FormatInt(*v, 10)
+		}
+	}
+	setOptionalDuration := func(name string, d *time.Duration, unit time.Duration) {
+		if d != nil {
+			m[name] = strconv.FormatInt(int64(*d/unit), 10)
+		}
+	}
+	setBool := func(name string, b *bool) {
+		if b == nil {
+			return
+		}
+		if *b {
+			m[name] = "1"
+		} else {
+			m[name] = "0"
+		}
+	}
+
+	switch c.Mode {
+	case ModeCaller:
+		m["mode"] = "caller"
+	case ModeListener:
+		m["mode"] = "listener"
+	case ModeRendezvouz:
+		m["mode"] = "rendezvous"
+	}
+	if c.Blocking {
+		m["blocking"] = "1"
+	} else if c.explicit["blocking"] {
+		m["blocking"] = "0"
+	}
+	setDuration("linger", c.Linger, time.Second)
+	setInt("pktsize", int64(c.PacketSize))
+	set("adapter", c.Adapter == "", c.Adapter)
+	setInt("localport", int64(c.LocalPort))
+
+	if c.TransType != 0 {
+		m["transtype"] = c.TransType.String()
+	}
+	setOptionalInt("maxbw", c.MaxBW)
+	setInt("pbkeylen", int64(c.PBKeyLen))
+	set("passphrase", c.Passphrase == "", c.Passphrase)
+	setInt("mss", int64(c.MSS))
+	setInt("fc", int64(c.FC))
+	setInt("sndbuf", int64(c.SndBuf))
+	setInt("rcvbuf", int64(c.RcvBuf))
+	setInt("ipttl", int64(c.IPTTL))
+	if c.IPTOS != nil {
+		m["iptos"] = strconv.Itoa(*c.IPTOS)
+	}
+	setInt("inputbw", c.InputBW)
+	setInt("oheadbw", int64(c.OHeadBW))
+	setOptionalDuration("latency", c.Latency, time.Millisecond)
+	setBool("tsbpdmode", c.TSBPDMode)
+	setBool("tlpktdrop", c.TLPktDrop)
+	setDuration("snddropdelay", c.SndDropDelay, time.Millisecond)
+	setBool("nakreport", c.NAKReport)
+	setDuration("conntimeo", c.ConnTimeout, time.Millisecond)
+	setInt("lossmaxttl", int64(c.LossMaxTTL))
+	setOptionalDuration("rcvlatency", c.RcvLatency, time.Millisecond)
+	setOptionalDuration("peerlatency", c.PeerLatency, time.Millisecond)
+	setInt("minversion", int64(c.MinVersion))
+	set("streamid", c.StreamID == "", c.StreamID)
+	set("congestion", c.Congestion == "", c.Congestion)
+	setBool("messageapi", c.MessageAPI)
+	setInt("payloadsize", int64(c.PayloadSize))
+	setInt("kmrefreshrate", int64(c.KMRefreshRate))
+	setInt("kmpreannounce", int64(c.KMPreAnnounce))
+	setBool("enforcedencryption", c.EnforcedEncryption)
+	setDuration("peeridletimeo", c.PeerIdleTimeout, time.Millisecond)
+	set("packetfilter", c.PacketFilter == "", c.PacketFilter)
+	setInt("udp_sndbuf", int64(c.UDPSndBuf))
+	setInt("udp_rcvbuf", int64(c.UDPRcvBuf))
+	setBool("ipv6only", c.IPv6Only)
+	set("bindtodevice", c.BindToDevice == "", c.BindToDevice)
+	setInt("retransmitalgo", int64(c.RetransmitAlgo))
+	setBool("drifttracer", c.DriftTracer)
+	setInt("mininputbw", c.MinInputBW)
+	if c.GroupConnect || c.explicit["groupconnect"] {
+		setBool("groupconnect", Bool(c.GroupConnect))
+	}
+	setDuration("groupminstabletimeo", c.GroupMinStableTimeout, time.Millisecond)
+	setInt("cryptomode", int64(c.CryptoMode))
+
+	return m
+}
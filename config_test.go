@@ -0,0 +1,175 @@
+package srtgo
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestConfigFromOptions(t *testing.T) {
+	options := map[string]string{
+		"mode":      "caller",
+		"blocking":  "0",
+		"transtype": "file",
+		"latency":   "200",
+		"maxbw":     "300000",
+		"tlpktdrop": "0",
+		"streamid":  "foo",
+		"linger":    "0",
+	}
+	c, err := ConfigFromOptions(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c.Mode != ModeCaller || c.Blocking || c.TransType != TransTypeFile {
+		t.Errorf("unexpected mode, blocking or transtype: %d %t %s", c.Mode, c.Blocking, c.TransType)
+	}
+	if c.Latency == nil || *c.Latency != 200*time.Millisecond {
+		t.Errorf("expected latency 200ms, got %v", c.Latency)
+	}
+	if c.MaxBW == nil || *c.MaxBW != 300000 {
+		t.Errorf("expected maxbw 300000, got %v", c.MaxBW)
+	}
+	if c.TLPktDrop == nil || *c.TLPktDrop {
+		t.Error("expected tlpktdrop to be explicitly disabled")
+	}
+	if c.StreamID != "foo" {
+		t.Errorf("expected streamid foo, got %s", c.StreamID)
+	}
+
+	// Explicit zero values must survive the conversion back to a map
+	if rendered := c.options(); !reflect.DeepEqual(rendered, options) {
+		t.Errorf("options did not round trip:\n got %v\nwant %v", rendered, options)
+	}
+}
+
+func TestConfigFromOptionsReportsEveryError(t *testing.T) {
+	_, err := ConfigFromOptions(map[string]string{
+		"latency":   "200ms",
+		"tlpktdrop": "true",
+		"transtype": "3",
+		"latencyy":  "200",
+		"rcvbuf":    "1000000",
+	})
+
+	var cerr ConfigError
+	if !errors.As(err, &cerr) {
+		t.Fatalf("expected a ConfigError, got %v", err)
+	}
+	bad := make(map[string]bool)
+	for _, oe := range cerr {
+		bad[oe.Option] = true
+	}
+	expected := map[string]bool{"latency": true, "tlpktdrop": true, "transtype": true, "latencyy": true}
+	if !reflect.DeepEqual(bad, expected) {
+		t.Errorf("got errors for %v, expected %v", bad, expected)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	valid := Config{
+		TransType:  TransTypeLive,
+		Latency:    Duration(120 * time.Millisecond),
+		PBKeyLen:   16,
+		Passphrase: "0123456789",
+		TLPktDrop:  Bool(false),
+	}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	invalid := Config{
+		Mode:        42,
+		PBKeyLen:    20,
+		Passphrase:  "short",
+		Latency:     Duration(-time.Millisecond),
+		PayloadSize: 2000,
+		Congestion:  "fast",
+	}
+	err := invalid.Validate()
+	var cerr ConfigError
+	if !errors.As(err, &cerr) {
+		t.Fatalf("expected a ConfigError, got %v", err)
+	}
+	if len(cerr) != 6 {
+		t.Errorf("expected 6 invalid options, got %d: %v", len(cerr), err)
+	}
+
+	//An explicit zero TTL is applied, and rejected by libsrt
+	for ttl, valid := range map[string]bool{"0": false, "1": true, "255": true, "256": false} {
+		c, err := ConfigFromOptions(map[string]string{"ipttl": ttl})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = c.Validate()
+		if valid && err != nil {
+			t.Errorf("ipttl %s: unexpected error: %v", ttl, err)
+		}
+		if !valid && (!errors.As(err, &cerr) || len(cerr) != 1 || cerr[0].Option != "ipttl") {
+			t.Errorf("ipttl %s: expected an invalid ipttl, got %v", ttl, err)
+		}
+	}
+}
+
+func TestConfigExplicitZero(t *testing.T) {
+	c := Config{
+		Latency:     Duration(0),
+		RcvLatency:  Duration(0),
+		PeerLatency: Duration(0),
+		MaxBW:       Int64(0),
+		IPTOS:       Int(0),
+	}
+	if err := c.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{"latency": "0", "rcvlatency": "0", "peerlatency": "0", "maxbw": "0", "iptos": "0"}
+	if rendered := c.options(); !reflect.DeepEqual(rendered, expected) {
+		t.Errorf("explicit zero values not applied:\n got %v\nwant %v", rendered, expected)
+	}
+	if rendered := (Config{}).options(); len(rendered) != 0 {
+		t.Errorf("unset fields applied: %v", rendered)
+	}
+}
+
+func TestNewSrtSocketWithConfig(t *testing.T) {
+	InitSRT()
+
+	s, err := NewSrtSocketWithConfig("127.0.0.1", 8090, Config{
+		TransType: TransTypeFile,
+		Latency:   Duration(300 * time.Millisecond),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	v, err := s.GetSockOptInt(SRTO_LATENCY)
+	if err != nil {
+		t.Error(err)
+	}
+	if v != 300 {
+		t.Errorf("expected SRTO_LATENCY 300, got %d", v)
+	}
+
+	if _, err := NewSrtSocketWithConfig("127.0.0.1", 8090, Config{PBKeyLen: 8}); err == nil {
+		t.Error("expected an error for an invalid configuration")
+	}
+}
+
+func TestNewSrtSocketE(t *testing.T) {
+	InitSRT()
+
+	s, err := NewSrtSocketE("127.0.0.1", 8090, map[string]string{"transtype": "file", "latency": "300"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	_, err = NewSrtSocketE("127.0.0.1", 8090, map[string]string{"latency": "200ms"})
+	var cerr ConfigError
+	if !errors.As(err, &cerr) {
+		t.Errorf("expected a ConfigError for an invalid latency, got %v", err)
+	}
+}
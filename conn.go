@@ -0,0 +1,264 @@
+package srtgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+// defaultListenBacklog is the backlog used by Listen
+const defaultListenBacklog = 10
+
+var (
+	_ net.Conn     = (*Conn)(nil)
+	_ net.Listener = (*Listener)(nil)
+)
+
+// Conn - net.Conn implementation on top of a connected SrtSocket
+type Conn struct {
+	s     *SrtSocket
+	laddr *net.UDPAddr
+	raddr *net.UDPAddr
+}
+
+// Listener - net.Listener implementation on top of a listening SrtSocket
+type Listener struct {
+	s    *SrtSocket
+	addr *net.UDPAddr
+}
+
+// Dial connects to the SRT listener at address ("host:port") in caller mode.
+// The options are the same as the ones accepted by NewSrtSocket, "mode" is
+// always forced to caller. Invalid options are reported like NewSrtSocketE does.
+func Dial(address string, options map[string]string) (*Conn, error) {
+	return DialContext(context.Background(), address, options)
+}
+
+// DialContext connects to the SRT listener at address like Dial, giving up
+// when ctx is done. Once connected, ctx has no effect on the connection.
+func DialContext(ctx context.Context, address string, options map[string]string) (*Conn, error) {
+	host, port, err := splitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := copyOptions(options)
+	opts["mode"] = "caller"
+
+	s, err := NewSrtSocketE(host, port, opts)
+	if err != nil {
+		return nil, fmt.Errorf("dial %s: %w", address, err)
+	}
+	if err := s.ConnectContext(ctx); err != nil {
+		s.Close()
+		return nil, fmt.Errorf("dial %s: %w", address, err)
+	}
+
+	return newConn(s, nil), nil
+}
+
+// Listen announces on the local address ("host:port", host may be empty to
+// listen on all interfaces) and returns a listener accepting SRT connections.
+// The options are the same as the ones accepted by NewSrtSocket, "mode" is
+// always forced to listener. Invalid options are reported like NewSrtSocketE
+// does.
+func Listen(address string, options map[string]string) (*Listener, error) {
+	host, port, err := splitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	if host == "" {
+		host = "0.0.0.0"
+	}
+
+	opts := copyOptions(options)
+	opts["mode"] = "listener"
+
+	s, err := NewSrtSocketE(host, port, opts)
+	if err != nil {
+		return nil, fmt.Errorf("listen %s: %w", address, err)
+	}
+	if err := s.Listen(defaultListenBacklog); err != nil {
+		s.Close()
+		return nil, fmt.Errorf("listen %s: %w", address, err)
+	}
+
+	l := &Listener{s: s}
+	l.addr, _ = s.LocalAddr()
+	return l, nil
+}
+
+// NewConn wraps an already connected SrtSocket, such as one returned by
+// Accept, into a net.Conn. The Conn takes ownership of the socket.
+func NewConn(s *SrtSocket) *Conn {
+	return newConn(s, nil)
+}
+
+func newConn(s *SrtSocket, raddr *net.UDPAddr) *Conn {
+	c := &Conn{s: s, raddr: raddr}
+	c.laddr, _ = s.LocalAddr()
+	if c.raddr == nil {
+		c.raddr, _ = s.RemoteAddr()
+	}
+	return c
+}
+
+// Socket - Return the underlying SRT socket
+func (c *Conn) Socket() *SrtSocket {
+	return c.s
+}
+
+// Read data from the connection. A connection closed by the peer is
+// reported as io.EOF, any other error as a *net.OpError (see opError).
+func (c *Conn) Read(b []byte) (int, error) {
+	n, err := c.s.Read(b)
+	if err != nil && errors.Is(err, EConnLost) {
+		return n, io.EOF
+	}
+	return n, c.opError("read", err)
+}
+
+// Write data to the connection. Errors are reported as a *net.OpError (see
+// opError).
+func (c *Conn) Write(b []byte) (int, error) {
+	n, err := c.s.Write(b)
+	return n, c.opError("write", err)
+}
+
+// ReadFrom - Write the data read from r to the connection, see
+// SrtSocket.ReadFrom
+func (c *Conn) ReadFrom(r io.Reader) (int64, error) {
+	return c.s.ReadFrom(r)
+}
+
+// WriteTo - Write the data read from the connection to w, see
+// SrtSocket.WriteTo
+func (c *Conn) WriteTo(w io.Writer) (int64, error) {
+	return c.s.WriteTo(w)
+}
+
+// Close the connection. Only the first call closes the socket, any later
+// call returns an error.
+func (c *Conn) Close() error {
+	return c.s.Close()
+}
+
+// LocalAddr - Return the local address of the connection
+func (c *Conn) LocalAddr() net.Addr {
+	if c.laddr == nil {
+		return nil
+	}
+	return c.laddr
+}
+
+// RemoteAddr - Return the address of the peer
+func (c *Conn) RemoteAddr() net.Addr {
+	if c.raddr == nil {
+		return nil
+	}
+	return c.raddr
+}
+
+// SetDeadline - Set read and write deadlines. Only supported in non-blocking mode.
+func (c *Conn) SetDeadline(t time.Time) error {
+	if c.s.blocking {
+		return errDeadlineBlocking
+	}
+	c.s.SetDeadline(t)
+	return nil
+}
+
+// SetReadDeadline - Set read deadline. Only supported in non-blocking mode.
+func (c *Conn) SetReadDeadline(t time.Time) error {
+	if c.s.blocking {
+		return errDeadlineBlocking
+	}
+	c.s.SetReadDeadline(t)
+	return nil
+}
+
+// SetWriteDeadline - Set write deadline. Only supported in non-blocking mode.
+func (c *Conn) SetWriteDeadline(t time.Time) error {
+	if c.s.blocking {
+		return errDeadlineBlocking
+	}
+	c.s.SetWriteDeadline(t)
+	return nil
+}
+
+// Socket - Return the underlying SRT socket
+func (l *Listener) Socket() *SrtSocket {
+	return l.s
+}
+
+// Accept waits for and returns the next connection to the listener. Errors
+// are reported as a *net.OpError (see opError).
+func (l *Listener) Accept() (net.Conn, error) {
+	s, raddr, err := l.s.Accept()
+	if err != nil {
+		return nil, opError("accept", l.Addr(), nil, err)
+	}
+	return newConn(s, raddr), nil
+}
+
+// Close the listener. Only the first call closes the socket, any later
+// call returns an error.
+func (l *Listener) Close() error {
+	return l.s.Close()
+}
+
+// Addr - Return the listener's local address
+func (l *Listener) Addr() net.Addr {
+	if l.addr == nil {
+		return nil
+	}
+	return l.addr
+}
+
+var errDeadlineBlocking = errors.New("deadlines are not supported on blocking sockets")
+
+func (c *Conn) opError(op string, err error) error {
+	return opError(op, c.LocalAddr(), c.RemoteAddr(), err)
+}
+
+// opError - Wrap err into a *net.OpError like the net package connections do,
+// so that callers written for them work unchanged: a closed socket is
+// reported as net.ErrClosed and an expired deadline as os.ErrDeadlineExceeded.
+func opError(op string, source, addr net.Addr, err error) error {
+	if err == nil {
+		return nil
+	}
+	var closed *SrtSocketClosed
+	var timeout *SrtEpollTimeout
+	if errors.As(err, &closed) {
+		err = net.ErrClosed
+	} else if errors.As(err, &timeout) {
+		err = os.ErrDeadlineExceeded
+	}
+	return &net.OpError{Op: op, Net: "srt", Source: source, Addr: addr, Err: err}
+}
+
+func splitHostPort(address string) (string, uint16, error) {
+	host, p, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", 0, err
+	}
+	port, err := strconv.ParseUint(p, 10, 16)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid port %q: %w", p, err)
+	}
+	return host, uint16(port), nil
+}
+
+func copyOptions(options map[string]string) map[string]string {
+	opts := make(map[string]string, len(options)+1)
+	for k, v := range options {
+		opts[k] = v
+	}
+	return opts
+}
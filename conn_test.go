@@ -0,0 +1,211 @@
+package srtgo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestConnDialListen(t *testing.T) {
+	InitSRT()
+
+	port := randomPort()
+	options := map[string]string{"blocking": "0", "transtype": "file"}
+	l, err := Listen(fmt.Sprintf("127.0.0.1:%d", port), options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if addr, ok := l.Addr().(*net.UDPAddr); !ok || addr.Port != int(port) {
+		t.Errorf("unexpected listener address %v", l.Addr())
+	}
+
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		c, err := l.Accept()
+		if err != nil {
+			t.Error(err)
+			close(accepted)
+			return
+		}
+		accepted <- c
+	}()
+
+	c, err := Dial(fmt.Sprintf("127.0.0.1:%d", port), options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	remote, ok := <-accepted
+	if !ok {
+		return
+	}
+	defer remote.Close()
+
+	if c.RemoteAddr().String() != remote.LocalAddr().String() {
+		t.Errorf("caller remote address %v does not match listener side local address %v", c.RemoteAddr(), remote.LocalAddr())
+	}
+	if c.LocalAddr().String() != remote.RemoteAddr().String() {
+		t.Errorf("caller local address %v does not match listener side remote address %v", c.LocalAddr(), remote.RemoteAddr())
+	}
+
+	msg := []byte("hello srt")
+	if _, err := c.Write(msg); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 1500)
+	remote.SetReadDeadline(time.Now().Add(time.Second))
+	n, err := remote.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf[:n], msg) {
+		t.Errorf("read %q, expected %q", buf[:n], msg)
+	}
+}
+
+func TestConnReadEOF(t *testing.T) {
+	InitSRT()
+
+	port := randomPort()
+	options := map[string]string{"blocking": "0", "transtype": "file"}
+	l, err := Listen(fmt.Sprintf("127.0.0.1:%d", port), options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		c, err := l.Accept()
+		if err != nil {
+			t.Error(err)
+			close(accepted)
+			return
+		}
+		accepted <- c
+	}()
+
+	c, err := Dial(fmt.Sprintf("127.0.0.1:%d", port), options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	remote, ok := <-accepted
+	if !ok {
+		c.Close()
+		return
+	}
+	defer remote.Close()
+
+	if err := c.Close(); err != nil {
+		t.Errorf("first close returned %v", err)
+	}
+	if err := c.Close(); err == nil {
+		t.Error("second close should return an error")
+	}
+
+	remote.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, err = remote.Read(make([]byte, 1500))
+	if err != io.EOF {
+		t.Errorf("expected io.EOF after peer close, got %v", err)
+	}
+}
+
+func TestConnErrors(t *testing.T) {
+	InitSRT()
+
+	caller, _ := socketPair(t, map[string]string{"blocking": "0", "transtype": "file"})
+	c := NewConn(caller)
+
+	c.SetReadDeadline(time.Now().Add(-time.Second))
+	_, err := c.Read(make([]byte, 1500))
+	var nerr net.Error
+	if !errors.Is(err, os.ErrDeadlineExceeded) || !errors.As(err, &nerr) || !nerr.Timeout() {
+		t.Errorf("expired read deadline reported as %v", err)
+	}
+
+	c.Close()
+	if _, err := c.Read(make([]byte, 1500)); !errors.Is(err, net.ErrClosed) {
+		t.Errorf("read on a closed connection reported as %v", err)
+	}
+	if _, err := c.Write([]byte("hello")); !errors.Is(err, net.ErrClosed) {
+		t.Errorf("write on a closed connection reported as %v", err)
+	}
+
+	l, err := Listen(fmt.Sprintf("127.0.0.1:%d", randomPort()), map[string]string{"blocking": "0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Close()
+	var operr *net.OpError
+	if _, err := l.Accept(); !errors.Is(err, net.ErrClosed) || !errors.As(err, &operr) || operr.Op != "accept" {
+		t.Errorf("accept on a closed listener reported as %v", err)
+	}
+}
+
+func TestConnDeadlineBlocking(t *testing.T) {
+	InitSRT()
+
+	s := NewSrtSocket("127.0.0.1", randomPort(), map[string]string{"blocking": "1"})
+	if s == nil {
+		t.Fatal("Could not create a srt socket")
+	}
+	c := NewConn(s)
+	defer c.Close()
+
+	if err := c.SetDeadline(time.Now()); err == nil {
+		t.Error("SetDeadline on a blocking socket should fail")
+	}
+}
+
+func TestConnInvalidOptions(t *testing.T) {
+	InitSRT()
+
+	address := fmt.Sprintf("127.0.0.1:%d", randomPort())
+	options := map[string]string{"blocking": "0", "latency": "200ms"}
+	var cerr ConfigError
+	if _, err := Listen(address, options); !errors.As(err, &cerr) {
+		t.Errorf("Listen: expected a ConfigError, got %v", err)
+	}
+	if _, err := Dial(address, options); !errors.As(err, &cerr) {
+		t.Errorf("Dial: expected a ConfigError, got %v", err)
+	}
+}
+
+func TestSplitHostPort(t *testing.T) {
+	for _, tc := range []struct {
+		address string
+		host    string
+		port    uint16
+		fail    bool
+	}{
+		{"127.0.0.1:8090", "127.0.0.1", 8090, false},
+		{":8090", "", 8090, false},
+		{"[::1]:9000", "::1", 9000, false},
+		{"localhost", "", 0, true},
+		{"localhost:70000", "", 0, true},
+	} {
+		host, port, err := splitHostPort(tc.address)
+		if tc.fail {
+			if err == nil {
+				t.Errorf("%s: expected an error", tc.address)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tc.address, err)
+			continue
+		}
+		if host != tc.host || port != tc.port {
+			t.Errorf("%s: got %s %d, expected %s %d", tc.address, host, port, tc.host, tc.port)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package srtgo
+
+import (
+	"context"
+	"net"
+)
+
+// watchContext - Call interrupt when ctx is done, until the returned stop
+// function is called. stop reports whether interrupt was called.
+func watchContext(ctx context.Context, interrupt func()) (stop func() bool) {
+	if ctx.Done() == nil {
+		return func() bool { return false }
+	}
+	done := make(chan struct{})
+	interrupted := make(chan bool, 1)
+	go func() {
+		select {
+		case <-ctx.Done():
+			interrupt()
+			interrupted <- true
+		case <-done:
+			interrupted <- false
+		}
+	}()
+	return func() bool {
+		close(done)
+		return <-interrupted
+	}
+}
+
+// withContext - Run op, a poll operation, interrupting its wait as soon as ctx
+// is done, in which case ctx.Err() is returned.
+//
+// Only the wait of op is interrupted: the deadlines set with SetDeadline still
+// apply to it, and the other operations on the socket are left alone.
+// Blocking sockets do not wait through the poll server, ctx is only checked
+// before op runs.
+func (s *SrtSocket) withContext(ctx context.Context, op func(done <-chan struct{}) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	err := op(ctx.Done())
+	if err == errWaitInterrupted {
+		return ctx.Err()
+	}
+	return err
+}
+
+// ConnectContext - Connect to a remote endpoint like Connect, giving up when
+// ctx is done, in which case ctx.Err() is returned.
+// Unlike the other context-aware methods, ConnectContext also interrupts
+// blocking sockets: the socket is closed with Close when ctx is done.
+func (s *SrtSocket) ConnectContext(ctx context.Context) error {
+	if !s.blocking {
+		return s.withContext(ctx, s.connect)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	stop := watchContext(ctx, func() {
+		s.Close()
+	})
+	err := s.connect(nil)
+	if stop() {
+		return ctx.Err()
+	}
+	return err
+}
+
+// AcceptContext - Accept an incoming connection like Accept, giving up when
+// ctx is done, in which case ctx.Err() is returned.
+// On blocking sockets, ctx is only checked before waiting.
+func (s *SrtSocket) AcceptContext(ctx context.Context) (*SrtSocket, *net.UDPAddr, error) {
+	var (
+		socket *SrtSocket
+		addr   *net.UDPAddr
+	)
+	err := s.withContext(ctx, func(done <-chan struct{}) (err error) {
+		if s.isClosed() {
+			return &SrtSocketClosed{}
+		}
+		socket, addr, err = s.accept(done)
+		return s.closedError(err)
+	})
+	return socket, addr, err
+}
+
+// ReadContext - Read data from the SRT socket like Read, giving up when ctx
+// is done, in which case ctx.Err() is returned.
+// On blocking sockets, ctx is only checked before reading.
+func (s *SrtSocket) ReadContext(ctx context.Context, b []byte) (n int, err error) {
+	err = s.withContext(ctx, func(done <-chan struct{}) (err error) {
+		n, err = s.recv(b, nil, done)
+		return
+	})
+	return
+}
+
+// WriteContext - Write data to the SRT socket like Write, giving up when ctx
+// is done, in which case ctx.Err() is returned.
+// On blocking sockets, ctx is only checked before writing.
+func (s *SrtSocket) WriteContext(ctx context.Context, b []byte) (n int, err error) {
+	err = s.withContext(ctx, func(done <-chan struct{}) (err error) {
+		n, err = s.send(b, nil, done)
+		return
+	})
+	return
+}
@@ -0,0 +1,212 @@
+package srtgo
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestReadContextCancel(t *testing.T) {
+	InitSRT()
+
+	s := connectedPair(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		cancel()
+	}()
+
+	buf := make([]byte, 1316)
+	start := time.Now()
+	_, err := s.ReadContext(ctx, buf)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if d := time.Since(start); d > 2*time.Second {
+		t.Errorf("ReadContext returned %v after cancellation", d)
+	}
+
+	//The cancellation must not leave an expired deadline behind
+	s.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	var timeout *SrtEpollTimeout
+	if _, err := s.Read(buf); !errors.As(err, &timeout) {
+		t.Errorf("expected a timeout after the read deadline, got %v", err)
+	}
+}
+
+func TestReadContextDeadline(t *testing.T) {
+	InitSRT()
+
+	s := connectedPair(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	buf := make([]byte, 1316)
+	if _, err := s.ReadContext(ctx, buf); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestConnectContextTimeout(t *testing.T) {
+	InitSRT()
+
+	//Nothing listens on that port, the connection would only fail after the
+	//connect timeout of libsrt
+	s := NewSrtSocket("127.0.0.1", randomPort(), map[string]string{"blocking": "0", "mode": "caller", "conntimeo": "10000"})
+	if s == nil {
+		t.Fatal("Could not create a srt socket")
+	}
+	defer s.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := s.ConnectContext(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if d := time.Since(start); d > 2*time.Second {
+		t.Errorf("ConnectContext returned after %v", d)
+	}
+}
+
+func TestConnectPollTimeout(t *testing.T) {
+	InitSRT()
+
+	s := NewSrtSocket("127.0.0.1", randomPort(), map[string]string{"blocking": "0", "mode": "caller", "conntimeo": "10000"})
+	if s == nil {
+		t.Fatal("Could not create a srt socket")
+	}
+	defer s.Close()
+
+	//Connect goes through ConnectContext, but keeps reporting an epoll timeout
+	s.SetPollTimeout(200 * time.Millisecond)
+	var timeout *SrtEpollTimeout
+	if err := s.Connect(); !errors.As(err, &timeout) {
+		t.Fatalf("expected a *SrtEpollTimeout, got %v", err)
+	}
+}
+
+func TestConnectContextBlocking(t *testing.T) {
+	InitSRT()
+
+	s := NewSrtSocket("127.0.0.1", randomPort(), map[string]string{"blocking": "1", "mode": "caller", "conntimeo": "10000"})
+	if s == nil {
+		t.Fatal("Could not create a srt socket")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if err := s.ConnectContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if d := time.Since(start); d > 2*time.Second {
+		t.Errorf("ConnectContext returned after %v", d)
+	}
+
+	//The socket was closed through Close
+	var closed *SrtSocketClosed
+	if err := s.Close(); !errors.As(err, &closed) {
+		t.Errorf("expected a *SrtSocketClosed error from Close, got %v", err)
+	}
+}
+
+func TestAcceptContext(t *testing.T) {
+	InitSRT()
+
+	ln := NewSrtSocket("127.0.0.1", randomPort(), map[string]string{"blocking": "0", "mode": "listener"})
+	if ln == nil {
+		t.Fatal("Could not create a srt socket")
+	}
+	defer ln.Close()
+	if err := ln.Listen(1); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, _, err := ln.AcceptContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		cancel()
+	}()
+	if _, _, err := ln.AcceptContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestWriteContext(t *testing.T) {
+	InitSRT()
+
+	//The remote never reads, so the writes block once the buffers are full
+	caller, _ := socketPair(t, map[string]string{"blocking": "0", "transtype": "file", "sndbuf": "1000000", "rcvbuf": "1000000", "linger": "0"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	buf := make([]byte, 1316)
+	var err error
+	for err == nil {
+		_, err = caller.WriteContext(ctx, buf)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	if n, err := caller.WriteContext(ctx, buf); n != 0 || !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled and nothing written, got %d, %v", n, err)
+	}
+}
+
+func TestDialContextCanceled(t *testing.T) {
+	InitSRT()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := DialContext(ctx, "127.0.0.1:"+strconv.Itoa(int(randomPort())), nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestWithContextLeavesDeadline(t *testing.T) {
+	s := &SrtSocket{pd: pdPool.Get().(*pollDesc)}
+	user := time.Now().Add(time.Hour)
+	s.SetReadDeadline(user)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err := s.withContext(ctx, func(done <-chan struct{}) error {
+		cancel()
+		//The wait of the operation is interrupted, not the socket
+		if err := s.pd.waitDone(ModeRead, done); err != errWaitInterrupted {
+			t.Errorf("expected the wait to be interrupted, got %v", err)
+		}
+		return errWaitInterrupted
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if d := s.pd.deadline(ModeRead); !d.Equal(user) {
+		t.Errorf("deadline %v after cancellation, expected %v", d, user)
+	}
+	if d := s.pd.deadline(ModeWrite); !d.IsZero() {
+		t.Errorf("write deadline %v was set", d)
+	}
+	if err := s.pd.checkPollErr(ModeRead); err != nil {
+		t.Errorf("the socket is left with %v after cancellation", err)
+	}
+	s.SetDeadline(time.Time{})
+}
@@ -0,0 +1,109 @@
+package srtgo
+
+import (
+	"errors"
+	"io"
+	"sync"
+)
+
+// Size of the writes of ReadFrom in file mode
+const fileCopyBufferSize = 1 << 20
+
+var (
+	liveBufferPool = sync.Pool{
+		New: func() interface{} {
+			b := make([]byte, maxLivePayloadSize)
+			return &b
+		},
+	}
+	fileBufferPool = sync.Pool{
+		New: func() interface{} {
+			b := make([]byte, fileCopyBufferSize)
+			return &b
+		},
+	}
+)
+
+// copyBuffer - Return a pooled buffer for ReadFrom and WriteTo, and the size of
+// the chunks to write: the maximum payload of a message in live mode, where
+// every write is sent as one message, or large chunks in file mode
+func (s *SrtSocket) copyBuffer() (pool *sync.Pool, buf []byte, chunk int) {
+	payload, err := s.GetSockOptInt(SRTO_PAYLOADSIZE)
+	if err != nil || payload <= 0 {
+		//File mode has no payload limit
+		pool = &fileBufferPool
+		buf = *pool.Get().(*[]byte)
+		return pool, buf, len(buf)
+	}
+	pool = &liveBufferPool
+	buf = *pool.Get().(*[]byte)
+	chunk = payload
+	if chunk > len(buf) {
+		chunk = len(buf)
+	}
+	return pool, buf, chunk
+}
+
+// ReadFrom - Write the data read from r to the socket until io.EOF, for
+// io.Copy. In live mode, each read from r, up to the payload size of the socket
+// (SRTO_PAYLOADSIZE), is sent as one message, so datagrams from a UDP source
+// are not split; in file mode, data is written in large chunks.
+func (s *SrtSocket) ReadFrom(r io.Reader) (n int64, err error) {
+	pool, buf, chunk := s.copyBuffer()
+	defer pool.Put(&buf)
+
+	for {
+		nr, rerr := r.Read(buf[:chunk])
+		if nr > 0 {
+			//In file mode, non-blocking writes may be partial
+			for b := buf[:nr]; len(b) > 0; {
+				nw, werr := s.Write(b)
+				n += int64(nw)
+				if werr != nil {
+					return n, werr
+				}
+				if nw == 0 {
+					return n, io.ErrShortWrite
+				}
+				b = b[nw:]
+			}
+		}
+		if rerr == io.EOF {
+			return n, nil
+		}
+		if rerr != nil {
+			return n, rerr
+		}
+	}
+}
+
+// WriteTo - Write the data read from the socket to w until the connection is
+// closed by the peer, for io.Copy. In live mode, each message is written to w
+// with a single Write, so a UDP destination gets one datagram per message.
+func (s *SrtSocket) WriteTo(w io.Writer) (n int64, err error) {
+	pool, buf, _ := s.copyBuffer()
+	defer pool.Put(&buf)
+
+	for {
+		nr, rerr := s.Read(buf)
+		if nr > 0 {
+			nw, werr := w.Write(buf[:nr])
+			n += int64(nw)
+			if werr != nil {
+				return n, werr
+			}
+			if nw != nr {
+				return n, io.ErrShortWrite
+			}
+		}
+		if rerr != nil {
+			if errors.Is(rerr, EConnLost) {
+				return n, nil
+			}
+			return n, rerr
+		}
+		if nr == 0 {
+			return n, nil
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package srtgo
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"testing"
+)
+
+var (
+	_ io.ReaderFrom = (*SrtSocket)(nil)
+	_ io.WriterTo   = (*SrtSocket)(nil)
+	_ io.ReaderFrom = (*Conn)(nil)
+	_ io.WriterTo   = (*Conn)(nil)
+)
+
+// chunkReader - Reader returning the datagrams of a UDP-like source, one per Read
+type chunkReader struct {
+	chunks [][]byte
+}
+
+func (r *chunkReader) Read(b []byte) (int, error) {
+	if len(r.chunks) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(b, r.chunks[0])
+	r.chunks = r.chunks[1:]
+	return n, nil
+}
+
+func TestReadFromLive(t *testing.T) {
+	caller, remote := socketPair(t, map[string]string{"blocking": "0", "transtype": "live"})
+
+	const count = 20
+	r := &chunkReader{}
+	for i := 0; i < count; i++ {
+		r.chunks = append(r.chunks, bytes.Repeat([]byte{byte(i)}, 188*7))
+	}
+	n, err := caller.ReadFrom(r)
+	if err != nil || n != count*188*7 {
+		t.Fatalf("ReadFrom wrote %d bytes: %v", n, err)
+	}
+
+	buf := make([]byte, 2048)
+	for i := 0; i < count; i++ {
+		n, err := remote.Read(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != 188*7 || buf[0] != byte(i) || buf[n-1] != byte(i) {
+			t.Fatalf("message %d: %d bytes starting with %d", i, n, buf[0])
+		}
+	}
+}
+
+func TestWriteToFile(t *testing.T) {
+	caller, remote := socketPair(t, map[string]string{"blocking": "0", "transtype": "file"})
+
+	data := make([]byte, 3<<20)
+	rand.Read(data)
+	sent := make(chan error, 1)
+	go func() {
+		//Closing lingers until the data is delivered in file mode
+		defer caller.Close()
+		_, err := caller.ReadFrom(bytes.NewReader(data))
+		sent <- err
+	}()
+
+	var got bytes.Buffer
+	n, err := remote.WriteTo(&got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := <-sent; err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(len(data)) || !bytes.Equal(got.Bytes(), data) {
+		t.Errorf("received %d bytes, differing from the %d sent", n, len(data))
+	}
+}
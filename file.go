@@ -0,0 +1,205 @@
+package srtgo
+
+/*
+#cgo LDFLAGS: -lsrt
+#include <stdlib.h>
+#include <srt/srt.h>
+*/
+import "C"
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"time"
+	"unsafe"
+)
+
+const (
+	// Blocks libsrt moves between the file and the socket buffers, its
+	// defaults for srt_sendfile and srt_recvfile
+	sendFileBlock = 364000
+	recvFileBlock = 7280000
+	// Maximum amount of data given to srt_sendfile at once by SendFile, so
+	// progress is reported and deadlines are checked regularly
+	sendFileChunk = 8 << 20
+	// Interval of the progress reports of RecvFile
+	recvFileProgressInterval = 100 * time.Millisecond
+)
+
+// FileProgressFunc - Function called by SendFile and RecvFile with the number
+// of bytes transferred so far, out of total
+type FileProgressFunc func(done, total int64)
+
+// waitReady - On non-blocking sockets, wait through the poll server until the
+// socket is ready for mode, with the deadline set for that mode
+func (s *SrtSocket) waitReady(mode PollMode) error {
+	if s.blocking {
+		return nil
+	}
+	event := C.SRT_EPOLL_IN
+	if mode == ModeWrite {
+		event = C.SRT_EPOLL_OUT
+	}
+	s.pd.reset(mode)
+	for {
+		//The poll server is edge-triggered, check the current state first
+		events, err := s.GetSockOptInt(SRTO_EVENT)
+		if err != nil {
+			return err
+		}
+		if events&int(event) != 0 {
+			return nil
+		}
+		if err := s.pd.wait(mode); err != nil {
+			return err
+		}
+	}
+}
+
+// SendFile - Send size bytes of f, starting at offset, with srt_sendfile. A
+// negative size sends the file up to its end. progress, which may be nil, is
+// called after every chunk sent.
+//
+// The socket must use the file transport type in stream mode (transtype file,
+// messageapi 0), and f must be reachable at f.Name(), which libsrt opens again.
+// srt_sendfile blocks until its data fits in the send buffer, even on
+// non-blocking sockets: those wait through the poll server, with the write
+// deadline, for room in the buffer before every chunk.
+// The number of bytes sent is returned, even on error.
+func (s *SrtSocket) SendFile(f *os.File, offset, size int64, progress FileProgressFunc) (int64, error) {
+	if s.isClosed() {
+		return 0, &SrtSocketClosed{}
+	}
+	n, err := s.sendFileChunks(f, offset, size, progress)
+	return n, s.closedError(err)
+}
+
+func (s *SrtSocket) sendFileChunks(f *os.File, offset, size int64, progress FileProgressFunc) (int64, error) {
+	if size < 0 {
+		fi, err := f.Stat()
+		if err != nil {
+			return 0, err
+		}
+		size = fi.Size() - offset
+	}
+	path := C.CString(f.Name())
+	defer C.free(unsafe.Pointer(path))
+
+	var sent int64
+	for sent < size {
+		chunk := size - sent
+		if chunk > sendFileChunk {
+			chunk = sendFileChunk
+		}
+		if !s.blocking {
+			if err := s.waitReady(ModeWrite); err != nil {
+				return sent, err
+			}
+			//Only give libsrt what the send buffer can take right away
+			if stats, err := s.PeekStats(); err == nil && stats.ByteAvailSndBuf > 0 && int64(stats.ByteAvailSndBuf) < chunk {
+				chunk = int64(stats.ByteAvailSndBuf)
+			}
+		}
+
+		n, err := s.sendFile(path, offset+sent, chunk)
+		sent += n
+		if err != nil {
+			return sent, err
+		}
+		if progress != nil {
+			progress(sent, size)
+		}
+	}
+	return sent, nil
+}
+
+func (s *SrtSocket) sendFile(path *C.char, offset, size int64) (int64, error) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	off := C.int64_t(offset)
+	n := C.srt_sendfile(s.socket, path, &off, C.int64_t(size), sendFileBlock)
+	if n == C.int64_t(SRT_ERROR) {
+		return 0, fmt.Errorf("Error in srt_sendfile: %w", srtGetAndClearError())
+	}
+	return int64(n), nil
+}
+
+// RecvFile - Receive size bytes into f with srt_recvfile. progress, which may
+// be nil, is called regularly with the size written to f so far, and once the
+// transfer completes.
+//
+// The socket must use the file transport type in stream mode (transtype file,
+// messageapi 0), and f must be reachable at f.Name(), which libsrt opens again
+// for writing and truncates. offset must therefore be 0: any other value would
+// lose the content of f before it, and is rejected without touching f.
+// srt_recvfile blocks until size bytes are received, even on non-blocking
+// sockets: those only wait through the poll server, with the read deadline,
+// for the first data to arrive.
+// The number of bytes received is returned.
+func (s *SrtSocket) RecvFile(f *os.File, offset, size int64, progress FileProgressFunc) (int64, error) {
+	if s.isClosed() {
+		return 0, &SrtSocketClosed{}
+	}
+	if offset != 0 {
+		return 0, fmt.Errorf("srtgo: RecvFile at offset %d: libsrt truncates the file, only offset 0 is supported", offset)
+	}
+	n, err := s.recvFileProgress(f, size, progress)
+	return n, s.closedError(err)
+}
+
+func (s *SrtSocket) recvFileProgress(f *os.File, size int64, progress FileProgressFunc) (int64, error) {
+	if err := s.waitReady(ModeRead); err != nil {
+		return 0, err
+	}
+	path := C.CString(f.Name())
+	defer C.free(unsafe.Pointer(path))
+
+	stop := func() {}
+	if progress != nil {
+		done := make(chan struct{})
+		stopped := make(chan struct{})
+		stop = func() {
+			close(done)
+			<-stopped
+		}
+		go func() {
+			defer close(stopped)
+			ticker := time.NewTicker(recvFileProgressInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-done:
+					return
+				case <-ticker.C:
+					if fi, err := f.Stat(); err == nil && fi.Size() > 0 {
+						received := fi.Size()
+						if received > size {
+							received = size
+						}
+						progress(received, size)
+					}
+				}
+			}
+		}()
+	}
+
+	n, err := s.recvFile(path, size)
+	//No report after the final one
+	stop()
+	if err == nil && progress != nil {
+		progress(n, size)
+	}
+	return n, err
+}
+
+func (s *SrtSocket) recvFile(path *C.char, size int64) (int64, error) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	var off C.int64_t
+	n := C.srt_recvfile(s.socket, path, &off, C.int64_t(size), recvFileBlock)
+	if n == C.int64_t(SRT_ERROR) {
+		return 0, fmt.Errorf("Error in srt_recvfile: %w", srtGetAndClearError())
+	}
+	return int64(n), nil
+}
@@ -0,0 +1,107 @@
+package srtgo
+
+import (
+	"bytes"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSendRecvFile(t *testing.T) {
+	const size = 3 << 20
+	data := make([]byte, size)
+	rand.Read(data)
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src")
+	if err := os.WriteFile(src, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	caller, remote := socketPair(t, map[string]string{"blocking": "0", "transtype": "file"})
+
+	sendErr := make(chan error, 1)
+	go func() {
+		f, err := os.Open(src)
+		if err != nil {
+			sendErr <- err
+			return
+		}
+		defer f.Close()
+		var last int64
+		n, err := caller.SendFile(f, 0, -1, func(done, total int64) {
+			if done <= last || total != size {
+				t.Errorf("progress %d/%d after %d", done, total, last)
+			}
+			last = done
+		})
+		if err == nil && (n != size || last != size) {
+			t.Errorf("sent %d bytes, last progress %d", n, last)
+		}
+		//Keep the connection up until the receiver acknowledges everything
+		if err == nil {
+			_, err = caller.Read(make([]byte, 1))
+		}
+		sendErr <- err
+	}()
+
+	dst, err := os.Create(filepath.Join(dir, "dst"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dst.Close()
+	var last int64
+	n, err := remote.RecvFile(dst, 0, size, func(done, total int64) {
+		if done < last || total != size {
+			t.Errorf("progress %d/%d after %d", done, total, last)
+		}
+		last = done
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != size || last != size {
+		t.Errorf("received %d bytes, last progress %d", n, last)
+	}
+	if _, err := remote.Write([]byte{1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-sendErr; err != nil {
+		t.Errorf("sending: %v", err)
+	}
+
+	got, err := os.ReadFile(dst.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Error("received file differs from the sent one")
+	}
+}
+
+func TestRecvFileOffset(t *testing.T) {
+	InitSRT()
+
+	path := filepath.Join(t.TempDir(), "dst")
+	if err := os.WriteFile(path, []byte("header"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	s := NewSrtSocket("127.0.0.1", randomPort(), map[string]string{"blocking": "0", "transtype": "file"})
+	if s == nil {
+		t.Fatal("Could not create a srt socket")
+	}
+	defer s.Close()
+
+	if _, err := s.RecvFile(f, 6, 100, nil); err == nil {
+		t.Error("RecvFile at a nonzero offset should fail")
+	}
+	if got, err := os.ReadFile(path); err != nil || string(got) != "header" {
+		t.Errorf("file content %q, %v after the rejected RecvFile", got, err)
+	}
+}
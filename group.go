@@ -0,0 +1,364 @@
+package srtgo
+
+/*
+#cgo LDFLAGS: -lsrt
+#include <stdlib.h>
+#include <string.h>
+#include <srt/srt.h>
+
+// Socket groups were added in libsrt 1.5.0. Older headers get stubs failing
+// like a libsrt built without bonding, so the package still builds against them.
+#if SRT_VERSION_VALUE >= SRT_MAKE_VERSION_VALUE(1, 5, 0)
+#define SRTGO_GROUPS 1
+
+static SRTSOCKET srtgo_create_group(int type) { return srt_create_group((SRT_GROUP_TYPE)type); }
+
+static SRTSOCKET srtgo_groupof(SRTSOCKET u) { return srt_groupof(u); }
+
+// Connect group to n endpoints at once, storing the IDs of the new members in ids
+static int srtgo_connect_group(SRTSOCKET group, const struct sockaddr_storage* addrs, const int* addrlens,
+	const uint16_t* weights, const int* tokens, SRTSOCKET* ids, int n)
+{
+	SRT_SOCKGROUPCONFIG* configs = calloc(n, sizeof(SRT_SOCKGROUPCONFIG));
+	if (configs == NULL)
+		return SRT_ERROR;
+	for (int i = 0; i < n; i++) {
+		configs[i] = srt_prepare_endpoint(NULL, (const struct sockaddr*)&addrs[i], addrlens[i]);
+		configs[i].weight = weights[i];
+		configs[i].token = tokens[i];
+	}
+	int res = srt_connect_group(group, configs, n);
+	for (int i = 0; i < n; i++)
+		ids[i] = configs[i].id;
+	free(configs);
+	return res;
+}
+
+// Return the members of group in the arrays of *size entries. When the arrays
+// are too small, fails with SRT_ELARGEMSG and sets *size to the member count.
+static int srtgo_group_data(SRTSOCKET group, SRTSOCKET* ids, struct sockaddr_storage* addrs, int* memberstates,
+	int* sockstates, uint16_t* weights, int* tokens, size_t* size)
+{
+	size_t n = *size;
+	SRT_SOCKGROUPDATA* data = calloc(n, sizeof(SRT_SOCKGROUPDATA));
+	if (data == NULL)
+		return SRT_ERROR;
+	int res = srt_group_data(group, data, size);
+	if (res != SRT_ERROR) {
+		for (size_t i = 0; i < *size && i < n; i++) {
+			ids[i] = data[i].id;
+			addrs[i] = data[i].peeraddr;
+			memberstates[i] = data[i].memberstate;
+			sockstates[i] = data[i].sockstate;
+			weights[i] = data[i].weight;
+			tokens[i] = data[i].token;
+		}
+	}
+	free(data);
+	return res;
+}
+#else
+#define SRTGO_GROUPS 0
+
+static SRTSOCKET srtgo_create_group(int type) { return SRT_INVALID_SOCK; }
+
+static SRTSOCKET srtgo_groupof(SRTSOCKET u) { return SRT_INVALID_SOCK; }
+
+static int srtgo_connect_group(SRTSOCKET group, const struct sockaddr_storage* addrs, const int* addrlens,
+	const uint16_t* weights, const int* tokens, SRTSOCKET* ids, int n) { return SRT_ERROR; }
+
+static int srtgo_group_data(SRTSOCKET group, SRTSOCKET* ids, struct sockaddr_storage* addrs, int* memberstates,
+	int* sockstates, uint16_t* weights, int* tokens, size_t* size) { return SRT_ERROR; }
+#endif
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"runtime"
+	"strconv"
+	"syscall"
+	"unsafe"
+)
+
+// GroupType - Type of an SRT socket group
+type GroupType int
+
+// Socket group types. Their values shadow the SRT_GTYPE_* constants instead of
+// using the C ones, so the package still builds against the libsrt 1.4.2 headers,
+// which have no socket groups.
+const (
+	// Every member link carries the same data, the receiver keeps the first copy of each packet
+	GroupBroadcast GroupType = 1
+	// One member link is active, the others are idle and take over when it breaks
+	GroupBackup GroupType = 2
+)
+
+func (t GroupType) String() string {
+	switch t {
+	case GroupBroadcast:
+		return "broadcast"
+	case GroupBackup:
+		return "backup"
+	}
+	return "GroupType(" + strconv.Itoa(int(t)) + ")"
+}
+
+// MemberStatus - Status of a member link in a socket group
+type MemberStatus int
+
+// Member link status. Their values shadow the SRT_GST_* constants.
+const (
+	MemberPending MemberStatus = 0 // connection is in progress
+	MemberIdle    MemberStatus = 1 // connected, but not used for transmission
+	MemberRunning MemberStatus = 2 // connected and used for transmission
+	MemberBroken  MemberStatus = 3 // connection broken, the member is about to be removed
+)
+
+func (m MemberStatus) String() string {
+	switch m {
+	case MemberPending:
+		return "pending"
+	case MemberIdle:
+		return "idle"
+	case MemberRunning:
+		return "running"
+	case MemberBroken:
+		return "broken"
+	}
+	return "MemberStatus(" + strconv.Itoa(int(m)) + ")"
+}
+
+// groupMinVersion is the first libsrt release with socket groups
+var groupMinVersion = MakeSrtVersion(1, 5, 0)
+
+// groupsBuilt is whether the libsrt headers srtgo was built against have
+// socket groups
+const groupsBuilt = C.SRTGO_GROUPS != 0
+
+// srtGroupMask shadows SRTGROUP_MASK, the bit set in the IDs of socket groups
+const srtGroupMask = 1 << 30
+
+// SrtGroup - SRT socket group (bonding).
+//
+// A group is used like a single socket: Read, Write, Close, deadlines and
+// callbacks all work on it, while libsrt spreads the data over the member
+// links according to the group type. The members are connected with Connect
+// and AddMember, or come from a listener with the "groupconnect" option set
+// to "1", whose Accept returns a grouped connection (see SrtSocket.Group).
+//
+// Socket groups need libsrt 1.5.0 or newer, both the headers srtgo is built
+// against and the library linked at runtime, built with bonding support
+// (ENABLE_BONDING).
+type SrtGroup struct {
+	*SrtSocket
+	groupType GroupType
+}
+
+// GroupEndpoint - Remote endpoint of a group member link
+type GroupEndpoint struct {
+	Host string
+	Port uint16
+	// Weight of the link. In backup groups the weight is the link priority.
+	Weight uint16
+	// Token passed back to the connect callback of the group for this link
+	Token int
+}
+
+// GroupMember - Member link of a socket group, as returned by Members
+type GroupMember struct {
+	ID     int          // SRT socket of the member
+	Addr   *net.UDPAddr // Address of the peer
+	Status MemberStatus // Status of the link
+	State  SockState    // State of the member socket
+	Weight uint16       // Weight of the link
+	Token  int          // Token given when the link was added
+}
+
+// NewSrtGroup - Create a new SRT socket group in caller mode. The options are
+// the same as the ones accepted by NewSrtSocket, and are applied to every
+// member; invalid options are reported as a ConfigError.
+func NewSrtGroup(groupType GroupType, options map[string]string) (*SrtGroup, error) {
+	if !groupsBuilt {
+		return nil, fmt.Errorf("socket groups require libsrt %s or newer, srtgo was built against older headers", groupMinVersion)
+	}
+	if linked := LibVersion(); linked < groupMinVersion {
+		return nil, fmt.Errorf("socket groups require libsrt %s or newer, linked libsrt is %s", groupMinVersion, linked)
+	}
+
+	config, err := ConfigFromOptions(options)
+	if err != nil {
+		return nil, err
+	}
+	config.Mode = ModeCaller
+
+	id, err := srtCreateGroup(groupType)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := initSrtSocket(id, "", 0, config)
+	if err != nil {
+		C.srt_close(id)
+		return nil, err
+	}
+	return &SrtGroup{SrtSocket: s, groupType: groupType}, nil
+}
+
+func srtCreateGroup(groupType GroupType) (C.SRTSOCKET, error) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	id := C.srtgo_create_group(C.int(groupType))
+	if id == SRT_INVALID_SOCK {
+		return id, fmt.Errorf("Error in srt_create_group: %w", srtGetAndClearError())
+	}
+	return id, nil
+}
+
+// IsGroup - Return whether the socket is a socket group, which is the case
+// for connections accepted from a group caller by a listener with the
+// "groupconnect" option set
+func (s *SrtSocket) IsGroup() bool {
+	return s.socket&srtGroupMask != 0
+}
+
+// Group - Return the socket as an SrtGroup, if it is a socket group
+func (s *SrtSocket) Group() (*SrtGroup, error) {
+	if !s.IsGroup() {
+		return nil, errors.New("not a socket group")
+	}
+	groupType, err := s.GetSockOptInt(SRTO_GROUPTYPE)
+	if err != nil {
+		return nil, err
+	}
+	return &SrtGroup{SrtSocket: s, groupType: GroupType(groupType)}, nil
+}
+
+// Type - Return the type of the group
+func (g *SrtGroup) Type() GroupType {
+	return g.groupType
+}
+
+// Connect the group to all the endpoints at once. In non-blocking mode,
+// Connect returns once the first member link is connected; the others keep
+// connecting in the background.
+func (g *SrtGroup) Connect(endpoints ...GroupEndpoint) error {
+	if len(endpoints) == 0 {
+		return errors.New("no endpoint to connect the group to")
+	}
+	if _, err := g.connectGroup(endpoints); err != nil {
+		return err
+	}
+
+	if !g.blocking {
+		if err := g.pd.wait(ModeWrite); err != nil {
+			return err
+		}
+	}
+
+	err := g.postconfiguration(g.SrtSocket)
+	if err != nil {
+		return fmt.Errorf("Error setting post socket options in connect")
+	}
+
+	return nil
+}
+
+// AddMember - Add a member link to a connected group. In non-blocking mode the
+// link connects in the background, use Members to follow its status.
+// Returns the ID of the new member.
+func (g *SrtGroup) AddMember(endpoint GroupEndpoint) (int, error) {
+	ids, err := g.connectGroup([]GroupEndpoint{endpoint})
+	if err != nil {
+		return 0, err
+	}
+	return ids[0], nil
+}
+
+func (g *SrtGroup) connectGroup(endpoints []GroupEndpoint) ([]int, error) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	n := len(endpoints)
+	addrs := make([]C.struct_sockaddr_storage, n)
+	addrlens := make([]C.int, n)
+	weights := make([]C.uint16_t, n)
+	tokens := make([]C.int, n)
+	for i, ep := range endpoints {
+		sa, salen, err := CreateAddrInet(ep.Host, ep.Port)
+		if err != nil {
+			return nil, err
+		}
+		C.memcpy(unsafe.Pointer(&addrs[i]), unsafe.Pointer(sa), C.size_t(salen))
+		addrlens[i] = C.int(salen)
+		weights[i] = C.uint16_t(ep.Weight)
+		tokens[i] = C.int(ep.Token)
+	}
+
+	memberIDs := make([]C.SRTSOCKET, n)
+	res := C.srtgo_connect_group(g.socket, &addrs[0], &addrlens[0], &weights[0], &tokens[0], &memberIDs[0], C.int(n))
+	if res == SRT_ERROR {
+		return nil, fmt.Errorf("Error in srt_connect_group: %w", srtGetAndClearError())
+	}
+
+	ids := make([]int, n)
+	for i, id := range memberIDs {
+		ids[i] = int(id)
+	}
+	return ids, nil
+}
+
+// RemoveMember - Close the member link with the given ID and remove it from the group
+func (g *SrtGroup) RemoveMember(id int) error {
+	if C.srtgo_groupof(C.SRTSOCKET(id)) != g.socket {
+		return fmt.Errorf("socket %d is not a member of the group", id)
+	}
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	if C.srt_close(C.SRTSOCKET(id)) == SRT_ERROR {
+		return fmt.Errorf("Error closing group member: %w", srtGetAndClearError())
+	}
+	return nil
+}
+
+// Members - Return the member links of the group with their status
+func (g *SrtGroup) Members() ([]GroupMember, error) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	size := C.size_t(8)
+	for {
+		ids := make([]C.SRTSOCKET, size)
+		addrs := make([]C.struct_sockaddr_storage, size)
+		memberStates := make([]C.int, size)
+		sockStates := make([]C.int, size)
+		weights := make([]C.uint16_t, size)
+		tokens := make([]C.int, size)
+		capacity := size
+		res := C.srtgo_group_data(g.socket, &ids[0], &addrs[0], &memberStates[0], &sockStates[0], &weights[0], &tokens[0], &size)
+		if res == SRT_ERROR {
+			err := srtGetAndClearError()
+			//The output arrays were too small, size now holds the member count
+			if errors.Is(err, ELargeMsg) && size > capacity {
+				continue
+			}
+			return nil, fmt.Errorf("Error in srt_group_data: %w", err)
+		}
+
+		members := make([]GroupMember, 0, int(size))
+		for i := 0; i < int(size); i++ {
+			addr, _ := udpAddrFromSockaddr((*syscall.RawSockaddrAny)(unsafe.Pointer(&addrs[i])))
+			members = append(members, GroupMember{
+				ID:     int(ids[i]),
+				Addr:   addr,
+				Status: MemberStatus(memberStates[i]),
+				State:  SockState(sockStates[i]),
+				Weight: uint16(weights[i]),
+				Token:  int(tokens[i]),
+			})
+		}
+		return members, nil
+	}
+}
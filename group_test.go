@@ -0,0 +1,212 @@
+package srtgo
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+// newTestGroup creates a group, skipping the test when the linked libsrt was
+// built without bonding support
+func newTestGroup(t *testing.T, groupType GroupType, options map[string]string) *SrtGroup {
+	g, err := NewSrtGroup(groupType, options)
+	if err != nil {
+		t.Skipf("socket groups are not available in the linked libsrt: %v", err)
+	}
+	return g
+}
+
+// acceptGroup starts a listener accepting grouped connections on port, and
+// returns the channel receiving the accepted group
+func acceptGroup(t *testing.T, port uint16) <-chan *SrtSocket {
+	listener := NewSrtSocket("127.0.0.1", port, map[string]string{"blocking": "0", "transtype": "live", "groupconnect": "1"})
+	if listener == nil {
+		t.Fatal("Could not create a srt socket")
+	}
+	t.Cleanup(func() { listener.Close() })
+	if err := listener.Listen(2); err != nil {
+		t.Fatal(err)
+	}
+
+	accepted := make(chan *SrtSocket, 1)
+	go func() {
+		s, _, err := listener.Accept()
+		if err != nil {
+			t.Error(err)
+			close(accepted)
+			return
+		}
+		accepted <- s
+	}()
+	return accepted
+}
+
+// waitMembers waits for count members of g to be connected
+func waitMembers(t *testing.T, g *SrtGroup, count int) []GroupMember {
+	var members []GroupMember
+	for i := 0; i < 50; i++ {
+		var err error
+		members, err = g.Members()
+		if err != nil {
+			t.Fatal(err)
+		}
+		connected := 0
+		for _, m := range members {
+			if m.Status == MemberRunning || m.Status == MemberIdle {
+				connected++
+			}
+		}
+		if connected == count {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if len(members) != count {
+		t.Fatalf("expected %d members, got %d", count, len(members))
+	}
+	return members
+}
+
+// checkGroupTransfer writes msg to g and checks it is read from remote
+func checkGroupTransfer(t *testing.T, g *SrtGroup, remote *SrtSocket, msg []byte) {
+	if _, err := g.Write(msg); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 1500)
+	remote.SetReadDeadline(time.Now().Add(time.Second))
+	n, err := remote.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf[:n], msg) {
+		t.Errorf("read %q, expected %q", buf[:n], msg)
+	}
+}
+
+func TestGroupBroadcast(t *testing.T) {
+	InitSRT()
+
+	port := randomPort()
+	options := map[string]string{"blocking": "0", "transtype": "live"}
+	g := newTestGroup(t, GroupBroadcast, options)
+	defer g.Close()
+
+	if g.Type() != GroupBroadcast || !g.IsGroup() {
+		t.Errorf("unexpected group type %s", g.Type())
+	}
+
+	accepted := acceptGroup(t, port)
+
+	err := g.Connect(
+		GroupEndpoint{Host: "127.0.0.1", Port: port, Weight: 1, Token: 1},
+		GroupEndpoint{Host: "127.0.0.1", Port: port, Weight: 1, Token: 2},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	remote, ok := <-accepted
+	if !ok {
+		return
+	}
+	defer remote.Close()
+
+	rg, err := remote.Group()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rg.Type() != GroupBroadcast {
+		t.Errorf("accepted group has type %s, expected broadcast", rg.Type())
+	}
+
+	members := waitMembers(t, g, 2)
+	checkGroupTransfer(t, g, remote, []byte("bonded"))
+
+	if err := g.RemoveMember(members[0].ID); err != nil {
+		t.Error(err)
+	}
+	if err := g.RemoveMember(members[0].ID); err == nil {
+		t.Error("removing a member twice should fail")
+	}
+}
+
+func TestGroupBackup(t *testing.T) {
+	InitSRT()
+
+	port := randomPort()
+	options := map[string]string{"blocking": "0", "transtype": "live"}
+	g := newTestGroup(t, GroupBackup, options)
+	defer g.Close()
+
+	if g.Type() != GroupBackup {
+		t.Errorf("unexpected group type %s", g.Type())
+	}
+
+	accepted := acceptGroup(t, port)
+
+	err := g.Connect(
+		GroupEndpoint{Host: "127.0.0.1", Port: port, Weight: 10, Token: 1},
+		GroupEndpoint{Host: "127.0.0.1", Port: port, Weight: 5, Token: 2},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	remote, ok := <-accepted
+	if !ok {
+		return
+	}
+	defer remote.Close()
+
+	rg, err := remote.Group()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rg.Type() != GroupBackup {
+		t.Errorf("accepted group has type %s, expected backup", rg.Type())
+	}
+
+	waitMembers(t, g, 2)
+	checkGroupTransfer(t, g, remote, []byte("main"))
+
+	// Only one link carries the data, the other one stands by
+	members, err := g.Members()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var active *GroupMember
+	for i, m := range members {
+		if m.Status == MemberRunning {
+			if active != nil {
+				t.Fatal("more than one active link in a backup group")
+			}
+			active = &members[i]
+		}
+	}
+	if active == nil {
+		t.Fatalf("no active link in %+v", members)
+	}
+
+	// The backup link takes over when the active one goes away
+	if err := g.RemoveMember(active.ID); err != nil {
+		t.Fatal(err)
+	}
+	checkGroupTransfer(t, g, remote, []byte("backup"))
+}
+
+func TestSocketIsNotGroup(t *testing.T) {
+	InitSRT()
+
+	s := NewSrtSocket("127.0.0.1", 8090, map[string]string{})
+	if s == nil {
+		t.Fatal("Could not create a srt socket")
+	}
+	defer s.Close()
+
+	if s.IsGroup() {
+		t.Error("a plain socket should not be a group")
+	}
+	if _, err := s.Group(); err == nil {
+		t.Error("Group on a plain socket should fail")
+	}
+}
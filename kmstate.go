@@ -0,0 +1,70 @@
+package srtgo
+
+// #cgo LDFLAGS: -lsrt
+// #include <srt/srt.h>
+import "C"
+
+import "strconv"
+
+// KMState - State of the key material exchange of an encrypted connection
+// (SRT_KM_STATE)
+type KMState int
+
+// Key material states
+const (
+	KMStateUnsecured = KMState(C.SRT_KM_S_UNSECURED) // no encryption
+	KMStateSecuring  = KMState(C.SRT_KM_S_SECURING)  // key exchange in progress
+	KMStateSecured   = KMState(C.SRT_KM_S_SECURED)   // encrypted, the keys match
+	KMStateNoSecret  = KMState(C.SRT_KM_S_NOSECRET)  // the peer is encrypted, but no passphrase is set here
+	KMStateBadSecret = KMState(C.SRT_KM_S_BADSECRET) // the passphrases do not match
+	// The cryptographic modes of the peers do not match. Added in SRT 1.5.2,
+	// so the value is not taken from the C headers.
+	KMStateBadCryptoMode = KMState(5)
+)
+
+func (k KMState) String() string {
+	switch k {
+	case KMStateUnsecured:
+		return "unsecured"
+	case KMStateSecuring:
+		return "securing"
+	case KMStateSecured:
+		return "secured"
+	case KMStateNoSecret:
+		return "nosecret"
+	case KMStateBadSecret:
+		return "badsecret"
+	case KMStateBadCryptoMode:
+		return "badcryptomode"
+	}
+	return "KMState(" + strconv.Itoa(int(k)) + ")"
+}
+
+// KMState - Return the key material state of the connection (SRTO_KMSTATE).
+// For a sender it is the sending state, for a receiver the receiving one.
+func (s *SrtSocket) KMState() (KMState, error) {
+	return s.kmState(SRTO_KMSTATE)
+}
+
+// SndKMState - Return the key material state of the sending direction
+// (SRTO_SNDKMSTATE)
+func (s *SrtSocket) SndKMState() (KMState, error) {
+	return s.kmState(SRTO_SNDKMSTATE)
+}
+
+// RcvKMState - Return the key material state of the receiving direction
+// (SRTO_RCVKMSTATE)
+func (s *SrtSocket) RcvKMState() (KMState, error) {
+	return s.kmState(SRTO_RCVKMSTATE)
+}
+
+func (s *SrtSocket) kmState(opt int) (KMState, error) {
+	v, err := s.GetSockOptInt(opt)
+	return KMState(v), err
+}
+
+// PBKeyLen - Return the length in bytes of the encryption key (SRTO_PBKEYLEN):
+// 16, 24 or 32, or 0 when the connection is not encrypted
+func (s *SrtSocket) PBKeyLen() (int, error) {
+	return s.GetSockOptInt(SRTO_PBKEYLEN)
+}
@@ -0,0 +1,65 @@
+package srtgo
+
+import (
+	"testing"
+)
+
+func TestWrongPassphrase(t *testing.T) {
+	ok := &SocketStatus{KMState: KMStateSecured, SndKMState: KMStateSecured, RcvKMState: KMStateSecured}
+	if !ok.Encrypted() || ok.WrongPassphrase(nil) {
+		t.Error("secured connection reported as not encrypted or with a wrong passphrase")
+	}
+	if !(&SocketStatus{RcvKMState: KMStateBadSecret}).WrongPassphrase(nil) {
+		t.Error("bad secret not reported")
+	}
+	if !(&SocketStatus{KMState: KMStateNoSecret}).WrongPassphrase(nil) {
+		t.Error("missing secret not reported")
+	}
+
+	undecrypted := *ok
+	undecrypted.PktRcvUndecryptTotal = 3
+	if !undecrypted.WrongPassphrase(ok) {
+		t.Error("undecrypted packets not reported")
+	}
+	if undecrypted.WrongPassphrase(&undecrypted) {
+		t.Error("no new undecrypted packets reported as a wrong passphrase")
+	}
+	if s := KMState(42).String(); s != "KMState(42)" {
+		t.Errorf("unknown state is %s", s)
+	}
+}
+
+// encryptedPair - Connect a caller and a listener with the given passphrases,
+// without enforcing encryption, and return the status of the accepted socket
+func encryptedPair(t *testing.T, listenerPassphrase, callerPassphrase string) *SocketStatus {
+	t.Helper()
+	_, remote := socketPairOptions(t,
+		map[string]string{"blocking": "0", "passphrase": listenerPassphrase, "enforcedencryption": "0"},
+		map[string]string{"blocking": "0", "passphrase": callerPassphrase, "enforcedencryption": "0"})
+
+	st, err := remote.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.State != SockStateConnected || st.RemoteAddr == nil || st.PeerVersion != LibVersion() {
+		t.Errorf("unexpected status %+v", st)
+	}
+	return st
+}
+
+func TestStatusEncrypted(t *testing.T) {
+	st := encryptedPair(t, "passphrase1234", "passphrase1234")
+	if !st.Encrypted() || st.WrongPassphrase(nil) {
+		t.Errorf("matching passphrases: %+v", st)
+	}
+	if st.PBKeyLen != 16 {
+		t.Errorf("key length %d, want the default 16", st.PBKeyLen)
+	}
+}
+
+func TestStatusBadSecret(t *testing.T) {
+	st := encryptedPair(t, "passphrase1234", "otherpassphrase")
+	if st.Encrypted() || !st.WrongPassphrase(nil) {
+		t.Errorf("mismatching passphrases: %+v", st)
+	}
+}
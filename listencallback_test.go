@@ -0,0 +1,108 @@
+package srtgo
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+// passphraseListener starts a listener choosing the passphrase of every
+// connection from the user in its stream ID, and accepting (and closing)
+// connections in the background
+func passphraseListener(t *testing.T, passphrases map[string]string) uint16 {
+	t.Helper()
+	port := randomPort()
+	ln := NewSrtSocket("127.0.0.1", port, map[string]string{"blocking": "0", "transtype": "live"})
+	if ln == nil {
+		t.Fatal("Could not create a srt socket")
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	ln.SetListenCallback(func(socket *SrtSocket, version int, addr *net.UDPAddr, streamid string) bool {
+		sid, err := ParseStreamID(streamid)
+		if err != nil {
+			socket.SetRejectReason(StreamIDRejectionReason(err))
+			return false
+		}
+		passphrase, ok := passphrases[sid.User]
+		if !ok {
+			socket.SetRejectReason(RejectionReasonUnauthorized)
+			return false
+		}
+		if err := socket.SetConnectionOptions(map[string]string{"passphrase": passphrase, "latency": "200"}); err != nil {
+			t.Error(err)
+			return false
+		}
+		return true
+	})
+	if err := ln.Listen(2); err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		for {
+			s, _, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			s.Close()
+		}
+	}()
+	return port
+}
+
+func dialWithPassphrase(port uint16, user, passphrase string) error {
+	s := NewSrtSocket("127.0.0.1", port, map[string]string{
+		"blocking":   "0",
+		"transtype":  "live",
+		"mode":       "caller",
+		"streamid":   "#!::u=" + user,
+		"passphrase": passphrase,
+	})
+	if s == nil {
+		return &SrtSocketClosed{}
+	}
+	defer s.Close()
+	s.SetPollTimeout(3 * time.Second)
+	return s.Connect()
+}
+
+func TestListenCallbackPassphrase(t *testing.T) {
+	InitSRT()
+
+	port := passphraseListener(t, map[string]string{
+		"alice": "alice-secret-key",
+		"bob":   "bob-secret-key!!",
+	})
+
+	if err := dialWithPassphrase(port, "alice", "alice-secret-key"); err != nil {
+		t.Errorf("alice with her passphrase: %v", err)
+	}
+	if err := dialWithPassphrase(port, "bob", "bob-secret-key!!"); err != nil {
+		t.Errorf("bob with his passphrase: %v", err)
+	}
+	var rejection *RejectionError
+	err := dialWithPassphrase(port, "alice", "bob-secret-key!!")
+	if !errors.As(err, &rejection) || rejection.Reason != RejectionReasonBadSecret {
+		t.Errorf("alice with the passphrase of bob: expected a bad secret rejection, got %v", err)
+	}
+	err = dialWithPassphrase(port, "carol", "carol-secret-key")
+	if !errors.As(err, &rejection) || rejection.Reason != RejectionReasonUnauthorized {
+		t.Errorf("unknown user: expected an unauthorized rejection, got %v", err)
+	}
+}
+
+func TestSetConnectionOptionsRejected(t *testing.T) {
+	InitSRT()
+
+	s := NewSrtSocket("127.0.0.1", randomPort(), map[string]string{"blocking": "0"})
+	if s == nil {
+		t.Fatal("Could not create a srt socket")
+	}
+	defer s.Close()
+
+	if err := s.SetConnectionOptions(map[string]string{"transtype": "file"}); err == nil {
+		t.Error("expected an error for an option that cannot be set per connection")
+	}
+}
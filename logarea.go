@@ -0,0 +1,187 @@
+package srtgo
+
+// #cgo LDFLAGS: -lsrt
+// #include <srt/srt.h>
+import "C"
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SrtLogFA - Functional area of libsrt logs
+type SrtLogFA int
+
+// Functional areas, see SrtAddLogFA. Their values shadow the SRT_LOGFA_*
+// constants of SRT 1.5 instead of using the C ones, so the package still
+// builds against older headers. libsrt 1.4 numbers its areas differently and
+// keeps them in a 32-bit set, so these values are only valid with libsrt 1.5
+// or newer, which the functions taking them check.
+const (
+	SrtLogFAGeneral  SrtLogFA = 0  // general uncategorized log, for serious issues only
+	SrtLogFASockMgmt SrtLogFA = 1  // socket create/open/close/configure activities
+	SrtLogFAConn     SrtLogFA = 2  // connection establishment and handshake
+	SrtLogFAXTimer   SrtLogFA = 3  // the checkTimer and around activities
+	SrtLogFATsbpd    SrtLogFA = 4  // the TsBPD thread
+	SrtLogFARsrc     SrtLogFA = 5  // system resource allocation and management
+	SrtLogFAHaiCrypt SrtLogFA = 6  // encryption
+	SrtLogFACongest  SrtLogFA = 7  // congestion control module
+	SrtLogFAPFilter  SrtLogFA = 8  // packet filter module
+	SrtLogFAAppLog   SrtLogFA = 10 // applications
+	SrtLogFAAPICtrl  SrtLogFA = 11 // API part for socket and library management
+	SrtLogFAQueCtrl  SrtLogFA = 13 // queue control activities
+	SrtLogFAEpollUpd SrtLogFA = 16 // epoll, internal update activities
+	SrtLogFAAPIRecv  SrtLogFA = 21 // API part for receiving
+	SrtLogFABufRecv  SrtLogFA = 22 // buffer, receiving side
+	SrtLogFAQueRecv  SrtLogFA = 23 // queue, receiving side
+	SrtLogFAChnRecv  SrtLogFA = 24 // CChannel, receiving side
+	SrtLogFAGrpRecv  SrtLogFA = 25 // group, receiving side
+	SrtLogFAAPISend  SrtLogFA = 31 // API part for sending
+	SrtLogFABufSend  SrtLogFA = 32 // buffer, sending side
+	SrtLogFAQueSend  SrtLogFA = 33 // queue, sending side
+	SrtLogFAChnSend  SrtLogFA = 34 // CChannel, sending side
+	SrtLogFAGrpSend  SrtLogFA = 35 // group, sending side
+	SrtLogFAInternal SrtLogFA = 41 // internal activities not connected directly to a socket
+	SrtLogFAQueMgmt  SrtLogFA = 43 // queue, management part
+	SrtLogFAChnMgmt  SrtLogFA = 44 // CChannel, management part
+	SrtLogFAGrpMgmt  SrtLogFA = 45 // group, management part
+	SrtLogFAEpollAPI SrtLogFA = 46 // epoll, API part
+)
+
+// logFANames - Names of the functional areas, as used by the libsrt tools
+var logFANames = map[SrtLogFA]string{
+	SrtLogFAGeneral:  "general",
+	SrtLogFASockMgmt: "sockmgmt",
+	SrtLogFAConn:     "conn",
+	SrtLogFAXTimer:   "xtimer",
+	SrtLogFATsbpd:    "tsbpd",
+	SrtLogFARsrc:     "rsrc",
+	SrtLogFAHaiCrypt: "haicrypt",
+	SrtLogFACongest:  "congest",
+	SrtLogFAPFilter:  "pfilter",
+	SrtLogFAAppLog:   "applog",
+	SrtLogFAAPICtrl:  "api_ctrl",
+	SrtLogFAQueCtrl:  "que_ctrl",
+	SrtLogFAEpollUpd: "epoll_upd",
+	SrtLogFAAPIRecv:  "api_recv",
+	SrtLogFABufRecv:  "buf_recv",
+	SrtLogFAQueRecv:  "que_recv",
+	SrtLogFAChnRecv:  "chn_recv",
+	SrtLogFAGrpRecv:  "grp_recv",
+	SrtLogFAAPISend:  "api_send",
+	SrtLogFABufSend:  "buf_send",
+	SrtLogFAQueSend:  "que_send",
+	SrtLogFAChnSend:  "chn_send",
+	SrtLogFAGrpSend:  "grp_send",
+	SrtLogFAInternal: "internal",
+	SrtLogFAQueMgmt:  "que_mgmt",
+	SrtLogFAChnMgmt:  "chn_mgmt",
+	SrtLogFAGrpMgmt:  "grp_mgmt",
+	SrtLogFAEpollAPI: "epoll_api",
+}
+
+func (fa SrtLogFA) String() string {
+	if name, ok := logFANames[fa]; ok {
+		return name
+	}
+	return "SrtLogFA(" + strconv.Itoa(int(fa)) + ")"
+}
+
+// ParseSrtLogFA - Parse a comma-separated list of functional area names, like
+// "conn,congest" (see SrtLogFA.String)
+func ParseSrtLogFA(list string) ([]SrtLogFA, error) {
+	var fas []SrtLogFA
+	for _, name := range strings.Split(list, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		found := false
+		for fa, n := range logFANames {
+			if n == name {
+				fas = append(fas, fa)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown log functional area %q", name)
+		}
+	}
+	return fas, nil
+}
+
+// logFAMinVersion - First libsrt version using the numbering of SrtLogFA
+var logFAMinVersion = MakeSrtVersion(1, 5, 0)
+
+// checkLogFASupported - Return an error when the linked libsrt predates the
+// numbering of SrtLogFA
+func checkLogFASupported() error {
+	if linked := LibVersion(); linked < logFAMinVersion {
+		return fmt.Errorf("log functional areas require libsrt %s or newer, linked libsrt is %s", logFAMinVersion, linked)
+	}
+	return nil
+}
+
+// SrtAddLogFA - Enable the logs of the given functional areas. Returns an
+// error, leaving the areas unchanged, when the linked libsrt is older than 1.5
+func SrtAddLogFA(fas ...SrtLogFA) error {
+	if err := checkLogFASupported(); err != nil {
+		return err
+	}
+	for _, fa := range fas {
+		C.srt_addlogfa(C.int(fa))
+	}
+	return nil
+}
+
+// SrtDelLogFA - Disable the logs of the given functional areas. Returns an
+// error, leaving the areas unchanged, when the linked libsrt is older than 1.5
+func SrtDelLogFA(fas ...SrtLogFA) error {
+	if err := checkLogFASupported(); err != nil {
+		return err
+	}
+	for _, fa := range fas {
+		C.srt_dellogfa(C.int(fa))
+	}
+	return nil
+}
+
+// SrtResetLogFA - Enable the logs of the given functional areas only, every
+// other area is disabled. Returns an error, leaving the areas unchanged, when
+// the linked libsrt is older than 1.5
+func SrtResetLogFA(fas ...SrtLogFA) error {
+	if err := checkLogFASupported(); err != nil {
+		return err
+	}
+	if len(fas) == 0 {
+		C.srt_resetlogfa(nil, 0)
+		return nil
+	}
+	cfas := make([]C.int, len(fas))
+	for i, fa := range fas {
+		cfas[i] = C.int(fa)
+	}
+	C.srt_resetlogfa(&cfas[0], C.size_t(len(cfas)))
+	return nil
+}
+
+// SrtLogFlags - Flags controlling the format of libsrt log messages
+type SrtLogFlags int
+
+// Log flags, see SrtSetLogFlags. Their values shadow the SRT_LOGF_* constants.
+const (
+	SrtLogFlagDisableTime       SrtLogFlags = 1 // no time in the messages
+	SrtLogFlagDisableThreadName SrtLogFlags = 2 // no thread name in the messages
+	SrtLogFlagDisableSeverity   SrtLogFlags = 4 // no severity in the messages
+	SrtLogFlagDisableEOL        SrtLogFlags = 8 // no end of line at the end of the messages
+)
+
+// SrtSetLogFlags - Set the flags controlling the format of libsrt log
+// messages, including the ones given to the log handler, e.g.
+// SrtLogFlagDisableTime|SrtLogFlagDisableEOL when the handler adds its own
+// timestamp and line breaks
+func SrtSetLogFlags(flags SrtLogFlags) {
+	C.srt_setlogflags(C.int(flags))
+}
@@ -0,0 +1,56 @@
+package srtgo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSrtLogFA(t *testing.T) {
+	fas, err := ParseSrtLogFA("conn, Congest,,haicrypt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []SrtLogFA{SrtLogFAConn, SrtLogFACongest, SrtLogFAHaiCrypt}; !reflect.DeepEqual(fas, want) {
+		t.Errorf("got %v, want %v", fas, want)
+	}
+	if _, err := ParseSrtLogFA("conn,nosuchfa"); err == nil {
+		t.Error("unknown area accepted")
+	}
+
+	for fa, name := range logFANames {
+		if fa.String() != name {
+			t.Errorf("%d named %s, want %s", int(fa), fa, name)
+		}
+		if got, err := ParseSrtLogFA(name); err != nil || len(got) != 1 || got[0] != fa {
+			t.Errorf("%s parsed as %v, %v", name, got, err)
+		}
+	}
+	if s := SrtLogFA(99).String(); s != "SrtLogFA(99)" {
+		t.Errorf("unknown area named %s", s)
+	}
+}
+
+func TestSrtLogFA(t *testing.T) {
+	if LibVersion() < logFAMinVersion {
+		if err := SrtAddLogFA(SrtLogFAConn); err == nil {
+			t.Errorf("functional area accepted by libsrt %s", LibVersion())
+		}
+		return
+	}
+	if err := SrtResetLogFA(SrtLogFAConn, SrtLogFACongest); err != nil {
+		t.Fatal(err)
+	}
+	if err := SrtAddLogFA(SrtLogFAHaiCrypt); err != nil {
+		t.Fatal(err)
+	}
+	if err := SrtDelLogFA(SrtLogFAConn); err != nil {
+		t.Fatal(err)
+	}
+	SrtSetLogFlags(SrtLogFlagDisableTime | SrtLogFlagDisableEOL)
+
+	//Leave every area enabled for the other tests
+	SrtSetLogFlags(0)
+	for fa := range logFANames {
+		SrtAddLogFA(fa)
+	}
+}
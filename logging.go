@@ -8,7 +8,10 @@ extern void srtLogCB(void* opaque, int level, const char* file, int line, const
 import "C"
 
 import (
+	"runtime"
 	"sync"
+	"sync/atomic"
+	"time"
 	"unsafe"
 
 	gopointer "github.com/mattn/go-pointer"
@@ -30,23 +33,103 @@ const (
 	SrtLogLevelTrace   SrtLogLevel = SrtLogLevel(8)
 )
 
+// DefaultLogQueueSize is the number of log messages SrtSetLogHandler queues
+// for the handler
+const DefaultLogQueueSize = 1024
+
 var (
 	logCBPtr     unsafe.Pointer = nil
 	logCBPtrLock sync.Mutex
+	logDropped   uint64
 )
 
+// logEntry - Log message of libsrt, waiting for delivery
+type logEntry struct {
+	time    time.Time
+	level   SrtLogLevel
+	file    string
+	line    int
+	area    string
+	message string
+}
+
+// logDelivery - Queue delivering the log messages of libsrt to a handler, in
+// order, from a single goroutine. Messages arriving when the queue is full are
+// dropped rather than blocking libsrt.
+type logDelivery struct {
+	entries chan logEntry
+	stop    chan struct{}
+	deliver func(logEntry)
+}
+
+func newLogDelivery(size int, deliver func(logEntry)) *logDelivery {
+	if size <= 0 {
+		size = DefaultLogQueueSize
+	}
+	d := &logDelivery{
+		entries: make(chan logEntry, size),
+		stop:    make(chan struct{}),
+		deliver: deliver,
+	}
+	go d.run()
+	return d
+}
+
+func (d *logDelivery) run() {
+	for {
+		select {
+		case e := <-d.entries:
+			d.deliver(e)
+		case <-d.stop:
+			//Deliver what was queued before the handler was replaced
+			for {
+				select {
+				case e := <-d.entries:
+					d.deliver(e)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
 //export srtLogCBWrapper
 func srtLogCBWrapper(arg unsafe.Pointer, level C.int, file *C.char, line C.int, area, message *C.char) {
-	userCB := gopointer.Restore(arg).(LogCallBackFunc)
-	go userCB(SrtLogLevel(level), C.GoString(file), int(line), C.GoString(area), C.GoString(message))
+	d := gopointer.Restore(arg).(*logDelivery)
+	d.push(logEntry{time.Now(), SrtLogLevel(level), C.GoString(file), int(line), C.GoString(area), C.GoString(message)})
+}
+
+// push - Queue e for delivery, or drop it when the queue is full
+func (d *logDelivery) push(e logEntry) {
+	select {
+	case d.entries <- e:
+	default:
+		atomic.AddUint64(&logDropped, 1)
+	}
 }
 
 func SrtSetLogLevel(level SrtLogLevel) {
 	C.srt_setloglevel(C.int(level))
 }
 
+// SrtSetLogHandler - Send the log messages of libsrt to cb. The messages are
+// delivered in order, from a single goroutine, through a queue of
+// DefaultLogQueueSize messages: when cb cannot keep up, the messages that do
+// not fit in the queue are dropped and counted by SrtLogDropped.
 func SrtSetLogHandler(cb LogCallBackFunc) {
-	ptr := gopointer.Save(cb)
+	SrtSetLogHandlerQueue(cb, DefaultLogQueueSize)
+}
+
+// SrtSetLogHandlerQueue - Like SrtSetLogHandler, with a queue of size messages
+func SrtSetLogHandlerQueue(cb LogCallBackFunc, size int) {
+	setLogDelivery(newLogDelivery(size, func(e logEntry) {
+		cb(e.level, e.file, e.line, e.area, e.message)
+	}))
+}
+
+func setLogDelivery(d *logDelivery) {
+	ptr := gopointer.Save(d)
 	C.srt_setloghandler(ptr, (*C.SRT_LOG_HANDLER_FN)(C.srtLogCB))
 	storeLogCBPtr(ptr)
 }
@@ -56,10 +139,29 @@ func SrtUnsetLogHandler() {
 	storeLogCBPtr(nil)
 }
 
+// SrtLogDropped - Return the number of log messages dropped because the log
+// handler could not keep up with libsrt
+func SrtLogDropped() uint64 {
+	return atomic.LoadUint64(&logDropped)
+}
+
+// logError - Report an error of srtgo through the log handler, with the
+// messages of libsrt. The error is dropped when no handler is set.
+func logError(err error) {
+	_, file, line, _ := runtime.Caller(1)
+	logCBPtrLock.Lock()
+	defer logCBPtrLock.Unlock()
+	if logCBPtr == nil {
+		return
+	}
+	gopointer.Restore(logCBPtr).(*logDelivery).push(logEntry{time.Now(), SrtLogLevelErr, file, line, "srtgo", err.Error()})
+}
+
 func storeLogCBPtr(ptr unsafe.Pointer) {
 	logCBPtrLock.Lock()
 	defer logCBPtrLock.Unlock()
 	if logCBPtr != nil {
+		close(gopointer.Restore(logCBPtr).(*logDelivery).stop)
 		gopointer.Unref(logCBPtr)
 	}
 	logCBPtr = ptr
@@ -0,0 +1,53 @@
+//go:build go1.21
+
+package srtgo
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+)
+
+// SlogLevel - Return the slog level matching a libsrt log level
+func SlogLevel(level SrtLogLevel) slog.Level {
+	switch {
+	case level <= SrtLogLevelCrit:
+		return slog.LevelError + 4
+	case level == SrtLogLevelErr:
+		return slog.LevelError
+	case level == SrtLogLevelWarning:
+		return slog.LevelWarn
+	case level == SrtLogLevelNotice:
+		return slog.LevelInfo + 2
+	case level == SrtLogLevelInfo:
+		return slog.LevelInfo
+	case level == SrtLogLevelDebug:
+		return slog.LevelDebug
+	}
+	return slog.LevelDebug - 4
+}
+
+// SrtSetSlogHandler - Send the log messages of libsrt to h, like
+// SrtSetLogHandler. Records carry the time libsrt logged the message, the
+// level mapped by SlogLevel, and the "file", "line" and "area" attributes.
+// The libsrt log level still has to be set with SrtSetLogLevel.
+func SrtSetSlogHandler(h slog.Handler) {
+	setLogDelivery(newLogDelivery(DefaultLogQueueSize, func(e logEntry) {
+		handleSlog(h, e)
+	}))
+}
+
+func handleSlog(h slog.Handler, e logEntry) {
+	ctx := context.Background()
+	level := SlogLevel(e.level)
+	if !h.Enabled(ctx, level) {
+		return
+	}
+	r := slog.NewRecord(e.time, level, strings.TrimRight(e.message, "\r\n"), 0)
+	r.AddAttrs(
+		slog.String("file", e.file),
+		slog.Int("line", e.line),
+		slog.String("area", e.area),
+	)
+	h.Handle(ctx, r)
+}
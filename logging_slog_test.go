@@ -0,0 +1,68 @@
+//go:build go1.21
+
+package srtgo
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+type recordHandler struct {
+	level   slog.Level
+	records []slog.Record
+}
+
+func (h *recordHandler) Enabled(_ context.Context, level slog.Level) bool { return level >= h.level }
+func (h *recordHandler) Handle(_ context.Context, r slog.Record) error {
+	h.records = append(h.records, r)
+	return nil
+}
+func (h *recordHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h *recordHandler) WithGroup(string) slog.Handler      { return h }
+
+func TestSlogLevel(t *testing.T) {
+	levels := []SrtLogLevel{SrtLogLevelCrit, SrtLogLevelErr, SrtLogLevelWarning, SrtLogLevelNotice,
+		SrtLogLevelInfo, SrtLogLevelDebug, SrtLogLevelTrace}
+	for i := 1; i < len(levels); i++ {
+		if SlogLevel(levels[i]) >= SlogLevel(levels[i-1]) {
+			t.Errorf("%d maps to %v, not below %v", levels[i], SlogLevel(levels[i]), SlogLevel(levels[i-1]))
+		}
+	}
+	if l := SlogLevel(SrtLogLevelErr); l != slog.LevelError {
+		t.Errorf("SrtLogLevelErr maps to %v", l)
+	}
+	if l := SlogLevel(SrtLogLevelWarning); l != slog.LevelWarn {
+		t.Errorf("SrtLogLevelWarning maps to %v", l)
+	}
+	if l := SlogLevel(SrtLogLevelInfo); l != slog.LevelInfo {
+		t.Errorf("SrtLogLevelInfo maps to %v", l)
+	}
+	if l := SlogLevel(SrtLogLevelDebug); l != slog.LevelDebug {
+		t.Errorf("SrtLogLevelDebug maps to %v", l)
+	}
+}
+
+func TestHandleSlog(t *testing.T) {
+	h := &recordHandler{level: slog.LevelInfo}
+	now := time.Now()
+	handleSlog(h, logEntry{now, SrtLogLevelDebug, "core.cpp", 10, "SRT.cn", "filtered\n"})
+	handleSlog(h, logEntry{now, SrtLogLevelWarning, "core.cpp", 42, "SRT.cn", "connection lost\n"})
+
+	if len(h.records) != 1 {
+		t.Fatalf("got %d records, want 1", len(h.records))
+	}
+	r := h.records[0]
+	if r.Level != slog.LevelWarn || r.Message != "connection lost" || !r.Time.Equal(now) {
+		t.Errorf("unexpected record %v %q %v", r.Level, r.Message, r.Time)
+	}
+	attrs := make(map[string]string)
+	r.Attrs(func(a slog.Attr) bool {
+		attrs[a.Key] = a.Value.String()
+		return true
+	})
+	if attrs["file"] != "core.cpp" || attrs["line"] != "42" || attrs["area"] != "SRT.cn" {
+		t.Errorf("unexpected attributes %v", attrs)
+	}
+}
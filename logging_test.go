@@ -0,0 +1,63 @@
+package srtgo
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestLogDeliveryOrder(t *testing.T) {
+	const n = 1000
+	got := make(chan string, n)
+	d := newLogDelivery(n, func(e logEntry) {
+		got <- e.message
+	})
+	defer close(d.stop)
+
+	for i := 0; i < n; i++ {
+		d.push(logEntry{message: strconv.Itoa(i)})
+	}
+	for i := 0; i < n; i++ {
+		select {
+		case msg := <-got:
+			if msg != strconv.Itoa(i) {
+				t.Fatalf("message %d delivered as %s", i, msg)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("message %d not delivered", i)
+		}
+	}
+}
+
+func TestLogDeliveryDrops(t *testing.T) {
+	block := make(chan struct{})
+	delivered := make(chan struct{}, 10)
+	d := newLogDelivery(2, func(e logEntry) {
+		<-block
+		delivered <- struct{}{}
+	})
+
+	before := SrtLogDropped()
+	//The first message is taken by the delivery goroutine, then blocks it
+	d.push(logEntry{})
+	for len(d.entries) != 0 {
+		time.Sleep(time.Millisecond)
+	}
+	for i := 0; i < 5; i++ {
+		d.push(logEntry{})
+	}
+	if dropped := SrtLogDropped() - before; dropped != 3 {
+		t.Errorf("dropped %d messages, want 3", dropped)
+	}
+
+	//Queued messages are still delivered once stopped
+	close(d.stop)
+	close(block)
+	for i := 0; i < 3; i++ {
+		select {
+		case <-delivered:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("only %d messages delivered, want 3", i)
+		}
+	}
+}
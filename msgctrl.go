@@ -0,0 +1,49 @@
+package srtgo
+
+// #cgo LDFLAGS: -lsrt
+// #include <srt/srt.h>
+import "C"
+
+import "time"
+
+// msgNoMask masks message numbers, which wrap around at 2^26
+const msgNoMask = 0x03ffffff
+
+// msgNoDropped - Return the number of messages between the message numbers
+// last and msgNo. Message numbers go from 1 to msgNoMask, 0 is skipped when
+// they wrap around.
+func msgNoDropped(last, msgNo int32) int32 {
+	dropped := (msgNo - last - 1) & msgNoMask
+	if msgNo < last {
+		dropped--
+	}
+	return dropped
+}
+
+// MsgCtrl - Message control information of a message, as in SRT_MSGCTRL
+type MsgCtrl struct {
+	// Time to live of the message, after which it is dropped if it was not
+	// sent yet. Zero (or negative) means infinite.
+	TTL time.Duration
+	// Deliver the message only after all the previous ones (message API in file mode)
+	InOrder bool
+	// Packet boundary flags of the message (SRT_MSGCTRL.boundary)
+	Boundary int
+	// Source time of the message, in microseconds on the SRT clock (see
+	// SrtTimeNow). Zero on write lets libsrt stamp the message when it is sent.
+	SrcTime int64
+
+	// Sequence number of the first packet of the message, set by ReadMsg
+	PktSeq int32
+	// Message number, set by ReadMsg
+	MsgNo int32
+	// Number of messages dropped between the previous ReadMsg and this one,
+	// from the gap in message numbers
+	Dropped int32
+}
+
+// SrtTimeNow - Return the current time of the SRT clock in microseconds, the
+// clock of MsgCtrl.SrcTime
+func SrtTimeNow() int64 {
+	return int64(C.srt_time_now())
+}
@@ -0,0 +1,92 @@
+package srtgo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReadWriteMsg(t *testing.T) {
+	InitSRT()
+
+	port := randomPort()
+	listener := NewSrtSocket("127.0.0.1", port, map[string]string{"blocking": "0", "transtype": "live"})
+	if listener == nil {
+		t.Fatal("Could not create a srt socket")
+	}
+	defer listener.Close()
+	if err := listener.Listen(1); err != nil {
+		t.Fatal(err)
+	}
+
+	caller := NewSrtSocket("127.0.0.1", port, map[string]string{"blocking": "0", "transtype": "live", "mode": "caller"})
+	if caller == nil {
+		t.Fatal("Could not create a srt socket")
+	}
+	defer caller.Close()
+
+	accepted := make(chan *SrtSocket, 1)
+	go func() {
+		s, _, err := listener.Accept()
+		if err != nil {
+			accepted <- nil
+			return
+		}
+		accepted <- s
+	}()
+
+	if err := caller.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	receiver := <-accepted
+	if receiver == nil {
+		t.Fatal("Accept failed")
+	}
+	defer receiver.Close()
+
+	srcTime := SrtTimeNow()
+	for i := 0; i < 3; i++ {
+		if _, err := caller.WriteMsg([]byte("hello"), MsgCtrl{SrcTime: srcTime, TTL: time.Second}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	receiver.SetReadDeadline(time.Now().Add(3 * time.Second))
+	buf := make([]byte, 1500)
+	var prev MsgCtrl
+	for i := 0; i < 3; i++ {
+		n, msg, err := receiver.ReadMsg(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(buf[:n]) != "hello" {
+			t.Errorf("read %q, expected %q", buf[:n], "hello")
+		}
+		if msg.Dropped != 0 {
+			t.Errorf("message %d: %d messages reported dropped", msg.MsgNo, msg.Dropped)
+		}
+		if i > 0 && msg.MsgNo != prev.MsgNo+1 {
+			t.Errorf("message number %d after %d", msg.MsgNo, prev.MsgNo)
+		}
+		if msg.SrcTime == 0 {
+			t.Error("source time not reported")
+		}
+		prev = msg
+	}
+}
+
+func TestMsgNoDropped(t *testing.T) {
+	for _, tc := range []struct {
+		last, msgNo, dropped int32
+	}{
+		{1, 2, 0},
+		{1, 5, 3},
+		{msgNoMask - 1, msgNoMask, 0},
+		{msgNoMask, 1, 0},
+		{msgNoMask, 3, 2},
+		{msgNoMask - 2, 2, 3},
+	} {
+		if dropped := msgNoDropped(tc.last, tc.msgNo); dropped != tc.dropped {
+			t.Errorf("from %#x to %#x: %d dropped, expected %d", tc.last, tc.msgNo, dropped, tc.dropped)
+		}
+	}
+}
@@ -6,6 +6,7 @@ package srtgo
 */
 import "C"
 import (
+	"errors"
 	"math"
 	"sync"
 	"sync/atomic"
@@ -41,6 +42,7 @@ const (
 	unblockRd: is used to unblock the poller when the socket becomes ready for io
 	rdState: polling state for read operations
 	rdDeadline: deadline in NS before poll operation times out, -1 means timedout (needs to be cleared), 0 is without timeout
+	rdAt: deadline as last set, zero without deadline
 	rdSeq: sequence number protects against spurious signalling of timeouts when timer is reset.
 	rdTimer: timer used to enforce deadline.
 
@@ -59,6 +61,7 @@ type pollDesc struct {
 	rdState    int32
 	rdLock     sync.Mutex
 	rdDeadline int64
+	rdAt       time.Time
 	rdSeq      int64
 	rdTimer    *time.Timer
 	rtSeq      int64
@@ -66,6 +69,7 @@ type pollDesc struct {
 	wrState    int32
 	wrLock     sync.Mutex
 	wdDeadline int64
+	wdAt       time.Time
 	wdSeq      int64
 	wdTimer    *time.Timer
 	wtSeq      int64
@@ -124,11 +128,23 @@ func (pd *pollDesc) release() {
 	//pool, so a leftover tick cannot be seen by the next socket to reuse it.
 	stopTimer(pd.rdTimer)
 	stopTimer(pd.wdTimer)
+	pd.rdAt = time.Time{}
+	pd.wdAt = time.Time{}
 	pd.fd = 0
 	pdPool.Put(pd)
 }
 
+// errWaitInterrupted is returned by waitDone when its done channel is closed
+var errWaitInterrupted = errors.New("srtgo: poll wait interrupted")
+
 func (pd *pollDesc) wait(mode PollMode) error {
+	return pd.waitDone(mode, nil)
+}
+
+// waitDone - Like wait, also giving up with errWaitInterrupted when done is
+// closed. Only the calling operation is interrupted: the deadlines and the
+// other waits on the socket are left alone. A nil done never fires.
+func (pd *pollDesc) waitDone(mode PollMode, done <-chan struct{}) error {
 	defer pd.reset(mode)
 	if err := pd.checkPollErr(mode); err != nil {
 		return err
@@ -169,6 +185,8 @@ wait:
 		select {
 		case <-unblockChan:
 			break wait
+		case <-done:
+			return errWaitInterrupted
 		case <-expiryChan:
 			pd.lock.Lock()
 			if mode == ModeRead {
@@ -240,6 +258,7 @@ func (pd *pollDesc) setDeadline(t time.Time, mode PollMode) {
 		pd.rtSeq = pd.rdSeq
 		stopTimer(pd.rdTimer)
 		pd.rdDeadline = d
+		pd.rdAt = t
 		if d > 0 {
 			pd.rdTimer.Reset(time.Duration(d))
 		}
@@ -252,6 +271,7 @@ func (pd *pollDesc) setDeadline(t time.Time, mode PollMode) {
 		pd.wtSeq = pd.wdSeq
 		stopTimer(pd.wdTimer)
 		pd.wdDeadline = d
+		pd.wdAt = t
 		if d > 0 {
 			pd.wdTimer.Reset(time.Duration(d))
 		}
@@ -261,6 +281,17 @@ func (pd *pollDesc) setDeadline(t time.Time, mode PollMode) {
 	}
 }
 
+// deadline returns the deadline last set for mode, ModeRead or ModeWrite, zero
+// when there is none.
+func (pd *pollDesc) deadline(mode PollMode) time.Time {
+	pd.lock.Lock()
+	defer pd.lock.Unlock()
+	if mode == ModeRead {
+		return pd.rdAt
+	}
+	return pd.wdAt
+}
+
 // unblock takes no pollDesc lock, on any path. setDeadline calls it while
 // holding pd.lock, so acquiring pd.lock here self-deadlocks on a non-reentrant
 // mutex -- the bug PR #72 fixed for the state update and left in the pollErr
@@ -15,7 +15,7 @@ func connectedPair(t *testing.T) *SrtSocket {
 	if ln == nil {
 		t.Fatal("failed to create listener socket")
 	}
-	t.Cleanup(ln.Close)
+	t.Cleanup(func() { ln.Close() })
 	if err := ln.Listen(1); err != nil {
 		t.Fatal("listen:", err)
 	}
@@ -38,7 +38,7 @@ func connectedPair(t *testing.T) *SrtSocket {
 	if err != nil {
 		t.Fatal("accept:", err)
 	}
-	t.Cleanup(remote.Close)
+	t.Cleanup(func() { remote.Close() })
 	return remote
 }
 
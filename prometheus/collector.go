@@ -0,0 +1,197 @@
+// Package prometheus exports the statistics of SRT sockets as Prometheus
+// metrics.
+//
+// It lives in its own module so that srtgo itself does not depend on the
+// Prometheus client library.
+package prometheus
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/haivision/srtgo"
+	prom "github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "srt"
+
+// Labels of every exported series. Sockets may share a stream ID and a peer,
+// like two callers of one listener, the socket ID keeps their series apart.
+var labelNames = []string{"socket_id", "stream_id", "peer", "mode"}
+
+type metric struct {
+	desc      *prom.Desc
+	valueType prom.ValueType
+	value     func(*srtgo.SrtStats) float64
+}
+
+func counter(name, help string, value func(*srtgo.SrtStats) float64) metric {
+	return metric{prom.NewDesc(prom.BuildFQName(namespace, "", name), help, labelNames, nil), prom.CounterValue, value}
+}
+
+func gauge(name, help string, value func(*srtgo.SrtStats) float64) metric {
+	return metric{prom.NewDesc(prom.BuildFQName(namespace, "", name), help, labelNames, nil), prom.GaugeValue, value}
+}
+
+// Multipliers converting SrtStats units to base units
+const (
+	mbps = 1e6
+	ms   = 1e-3
+	us   = 1e-6
+)
+
+var metrics = []metric{
+	counter("packets_sent_total", "Total number of sent data packets, including retransmissions (PktSentTotal)",
+		func(s *srtgo.SrtStats) float64 { return float64(s.PktSentTotal) }),
+	counter("packets_received_total", "Total number of received packets (PktRecvTotal)",
+		func(s *srtgo.SrtStats) float64 { return float64(s.PktRecvTotal) }),
+	counter("packets_send_lost_total", "Total number of lost packets, sender side (PktSndLossTotal)",
+		func(s *srtgo.SrtStats) float64 { return float64(s.PktSndLossTotal) }),
+	counter("packets_receive_lost_total", "Total number of lost packets, receiver side (PktRcvLossTotal)",
+		func(s *srtgo.SrtStats) float64 { return float64(s.PktRcvLossTotal) }),
+	counter("packets_retransmitted_total", "Total number of retransmitted packets (PktRetransTotal)",
+		func(s *srtgo.SrtStats) float64 { return float64(s.PktRetransTotal) }),
+	counter("packets_send_dropped_total", "Total number of too-late-to-send dropped packets (PktSndDropTotal)",
+		func(s *srtgo.SrtStats) float64 { return float64(s.PktSndDropTotal) }),
+	counter("packets_receive_dropped_total", "Total number of too-late-to-play missing packets (PktRcvDropTotal)",
+		func(s *srtgo.SrtStats) float64 { return float64(s.PktRcvDropTotal) }),
+	counter("packets_undecrypted_total", "Total number of undecrypted packets (PktRcvUndecryptTotal)",
+		func(s *srtgo.SrtStats) float64 { return float64(s.PktRcvUndecryptTotal) }),
+	counter("acks_sent_total", "Total number of sent ACK packets (PktSentACKTotal)",
+		func(s *srtgo.SrtStats) float64 { return float64(s.PktSentACKTotal) }),
+	counter("acks_received_total", "Total number of received ACK packets (PktRecvACKTotal)",
+		func(s *srtgo.SrtStats) float64 { return float64(s.PktRecvACKTotal) }),
+	counter("naks_sent_total", "Total number of sent NAK packets (PktSentNAKTotal)",
+		func(s *srtgo.SrtStats) float64 { return float64(s.PktSentNAKTotal) }),
+	counter("naks_received_total", "Total number of received NAK packets (PktRecvNAKTotal)",
+		func(s *srtgo.SrtStats) float64 { return float64(s.PktRecvNAKTotal) }),
+	counter("bytes_sent_total", "Total number of sent data bytes, including retransmissions (ByteSentTotal)",
+		func(s *srtgo.SrtStats) float64 { return float64(s.ByteSentTotal) }),
+	counter("bytes_received_total", "Total number of received bytes (ByteRecvTotal)",
+		func(s *srtgo.SrtStats) float64 { return float64(s.ByteRecvTotal) }),
+	counter("bytes_receive_lost_total", "Total number of lost bytes, receiver side (ByteRcvLossTotal)",
+		func(s *srtgo.SrtStats) float64 { return float64(s.ByteRcvLossTotal) }),
+	counter("bytes_retransmitted_total", "Total number of retransmitted bytes (ByteRetransTotal)",
+		func(s *srtgo.SrtStats) float64 { return float64(s.ByteRetransTotal) }),
+	counter("bytes_send_dropped_total", "Total number of too-late-to-send dropped bytes (ByteSndDropTotal)",
+		func(s *srtgo.SrtStats) float64 { return float64(s.ByteSndDropTotal) }),
+	counter("bytes_receive_dropped_total", "Total number of too-late-to-play missing bytes (ByteRcvDropTotal)",
+		func(s *srtgo.SrtStats) float64 { return float64(s.ByteRcvDropTotal) }),
+	counter("send_duration_seconds_total", "Total time spent sending data, idle time excluded (UsSndDurationTotal)",
+		func(s *srtgo.SrtStats) float64 { return float64(s.UsSndDurationTotal) * us }),
+
+	gauge("rtt_seconds", "Round trip time (MsRTT)",
+		func(s *srtgo.SrtStats) float64 { return s.MsRTT * ms }),
+	gauge("bandwidth_bits_per_second", "Estimated bandwidth of the link (MbpsBandwidth)",
+		func(s *srtgo.SrtStats) float64 { return s.MbpsBandwidth * mbps }),
+	gauge("max_bandwidth_bits_per_second", "Transmit bandwidth ceiling (MbpsMaxBW)",
+		func(s *srtgo.SrtStats) float64 { return s.MbpsMaxBW * mbps }),
+	gauge("send_rate_bits_per_second", "Sending rate (MbpsSendRate)",
+		func(s *srtgo.SrtStats) float64 { return s.MbpsSendRate * mbps }),
+	gauge("receive_rate_bits_per_second", "Receiving rate (MbpsRecvRate)",
+		func(s *srtgo.SrtStats) float64 { return s.MbpsRecvRate * mbps }),
+	gauge("flow_window_packets", "Flow window size (PktFlowWindow)",
+		func(s *srtgo.SrtStats) float64 { return float64(s.PktFlowWindow) }),
+	gauge("congestion_window_packets", "Congestion window size (PktCongestionWindow)",
+		func(s *srtgo.SrtStats) float64 { return float64(s.PktCongestionWindow) }),
+	gauge("flight_size_packets", "Number of packets in flight (PktFlightSize)",
+		func(s *srtgo.SrtStats) float64 { return float64(s.PktFlightSize) }),
+	gauge("send_buffer_available_bytes", "Available space in the sender buffer (ByteAvailSndBuf)",
+		func(s *srtgo.SrtStats) float64 { return float64(s.ByteAvailSndBuf) }),
+	gauge("receive_buffer_available_bytes", "Available space in the receiver buffer (ByteAvailRcvBuf)",
+		func(s *srtgo.SrtStats) float64 { return float64(s.ByteAvailRcvBuf) }),
+	gauge("send_buffer_packets", "Unacknowledged packets in the sender buffer (PktSndBuf)",
+		func(s *srtgo.SrtStats) float64 { return float64(s.PktSndBuf) }),
+	gauge("send_buffer_bytes", "Unacknowledged bytes in the sender buffer (ByteSndBuf)",
+		func(s *srtgo.SrtStats) float64 { return float64(s.ByteSndBuf) }),
+	gauge("send_buffer_seconds", "Timespan of the unacknowledged packets in the sender buffer (MsSndBuf)",
+		func(s *srtgo.SrtStats) float64 { return float64(s.MsSndBuf) * ms }),
+	gauge("receive_buffer_packets", "Undelivered packets in the receiver buffer (PktRcvBuf)",
+		func(s *srtgo.SrtStats) float64 { return float64(s.PktRcvBuf) }),
+	gauge("receive_buffer_bytes", "Undelivered bytes in the receiver buffer (ByteRcvBuf)",
+		func(s *srtgo.SrtStats) float64 { return float64(s.ByteRcvBuf) }),
+	gauge("receive_buffer_seconds", "Timespan of the undelivered packets in the receiver buffer (MsRcvBuf)",
+		func(s *srtgo.SrtStats) float64 { return float64(s.MsRcvBuf) * ms }),
+	gauge("send_tsbpd_delay_seconds", "Timestamp-based packet delivery delay, sender side (MsSndTsbPdDelay)",
+		func(s *srtgo.SrtStats) float64 { return float64(s.MsSndTsbPdDelay) * ms }),
+	gauge("receive_tsbpd_delay_seconds", "Timestamp-based packet delivery delay, receiver side (MsRcvTsbPdDelay)",
+		func(s *srtgo.SrtStats) float64 { return float64(s.MsRcvTsbPdDelay) * ms }),
+}
+
+// Collector - prometheus.Collector exporting the statistics of a set of
+// tracked SRT sockets, labelled with their socket ID, stream ID, peer address
+// and mode.
+//
+// Collecting does not clear the interval measurements returned by
+// SrtSocket.Stats. Closed sockets are untracked, and their series removed, on
+// the next collection.
+type Collector struct {
+	mu      sync.Mutex
+	sockets map[*srtgo.SrtSocket][]string
+}
+
+var _ prom.Collector = (*Collector)(nil)
+
+// NewCollector - Create a collector tracking no socket, to register with a
+// prometheus.Registerer
+func NewCollector() *Collector {
+	return &Collector{sockets: make(map[*srtgo.SrtSocket][]string)}
+}
+
+// Track - Export the statistics of a connected socket. The labels are read
+// once, when the socket is tracked.
+func (c *Collector) Track(s *srtgo.SrtSocket) {
+	streamID, _ := s.GetSockOptString(srtgo.SRTO_STREAMID)
+	peer := ""
+	if addr, err := s.RemoteAddr(); err == nil {
+		peer = addr.String()
+	}
+	labels := []string{strconv.Itoa(s.ID()), streamID, peer, modeName(s.Mode())}
+
+	c.mu.Lock()
+	c.sockets[s] = labels
+	c.mu.Unlock()
+}
+
+// Untrack - Stop exporting the statistics of a socket
+func (c *Collector) Untrack(s *srtgo.SrtSocket) {
+	c.mu.Lock()
+	delete(c.sockets, s)
+	c.mu.Unlock()
+}
+
+// Describe - Implement prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prom.Desc) {
+	for _, m := range metrics {
+		ch <- m.desc
+	}
+}
+
+// Collect - Implement prometheus.Collector
+func (c *Collector) Collect(ch chan<- prom.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for s, labels := range c.sockets {
+		stats, err := s.PeekStats()
+		if err != nil {
+			//The socket is closed (or broken), drop its series
+			delete(c.sockets, s)
+			continue
+		}
+		for _, m := range metrics {
+			ch <- prom.MustNewConstMetric(m.desc, m.valueType, m.value(stats), labels...)
+		}
+	}
+}
+
+func modeName(mode int) string {
+	switch mode {
+	case srtgo.ModeCaller:
+		return "caller"
+	case srtgo.ModeListener:
+		return "listener"
+	case srtgo.ModeRendezvouz:
+		return "rendezvous"
+	}
+	return "unknown"
+}
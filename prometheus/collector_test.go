@@ -0,0 +1,133 @@
+package prometheus
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/haivision/srtgo"
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func randomPort() uint16 {
+	return uint16(rand.Intn(10000) + 20000)
+}
+
+func TestCollectorEmpty(t *testing.T) {
+	c := NewCollector()
+	if n := testutil.CollectAndCount(c); n != 0 {
+		t.Errorf("collected %d series without tracked sockets", n)
+	}
+	problems, err := testutil.CollectAndLint(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range problems {
+		t.Errorf("%s: %s", p.Metric, p.Text)
+	}
+}
+
+func TestCollectorTrack(t *testing.T) {
+	srtgo.InitSRT()
+
+	port := randomPort()
+	listener := srtgo.NewSrtSocket("127.0.0.1", port, map[string]string{"blocking": "0", "transtype": "live"})
+	if listener == nil {
+		t.Fatal("Could not create a srt socket")
+	}
+	defer listener.Close()
+	if err := listener.Listen(1); err != nil {
+		t.Fatal(err)
+	}
+
+	caller := srtgo.NewSrtSocket("127.0.0.1", port, map[string]string{
+		"blocking":  "0",
+		"transtype": "live",
+		"mode":      "caller",
+		"streamid":  "camera1",
+	})
+	if caller == nil {
+		t.Fatal("Could not create a srt socket")
+	}
+	defer caller.Close()
+
+	go func() {
+		s, _, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		time.Sleep(time.Second)
+		s.Close()
+	}()
+
+	caller.SetPollTimeout(3 * time.Second)
+	if err := caller.Connect(); err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewCollector()
+	c.Track(caller)
+	if n := testutil.CollectAndCount(c, "srt_packets_sent_total"); n != 1 {
+		t.Errorf("collected %d srt_packets_sent_total series, expected 1", n)
+	}
+	if n := testutil.CollectAndCount(c); n != len(metrics) {
+		t.Errorf("collected %d series, expected %d", n, len(metrics))
+	}
+
+	caller.Close()
+	if n := testutil.CollectAndCount(c); n != 0 {
+		t.Errorf("collected %d series after the socket was closed", n)
+	}
+}
+
+func TestCollectorSameLabels(t *testing.T) {
+	srtgo.InitSRT()
+
+	port := randomPort()
+	listener := srtgo.NewSrtSocket("127.0.0.1", port, map[string]string{"blocking": "0", "transtype": "live"})
+	if listener == nil {
+		t.Fatal("Could not create a srt socket")
+	}
+	defer listener.Close()
+	if err := listener.Listen(2); err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		for i := 0; i < 2; i++ {
+			s, _, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer s.Close()
+		}
+		time.Sleep(time.Second)
+	}()
+
+	//Same stream ID and peer for both callers
+	c := NewCollector()
+	for i := 0; i < 2; i++ {
+		caller := srtgo.NewSrtSocket("127.0.0.1", port, map[string]string{"blocking": "0", "transtype": "live", "mode": "caller"})
+		if caller == nil {
+			t.Fatal("Could not create a srt socket")
+		}
+		defer caller.Close()
+		caller.SetPollTimeout(3 * time.Second)
+		if err := caller.Connect(); err != nil {
+			t.Fatal(err)
+		}
+		c.Track(caller)
+	}
+
+	reg := prom.NewRegistry()
+	reg.MustRegister(c)
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range families {
+		if len(f.Metric) != 2 {
+			t.Errorf("%s: %d series, expected 2", f.GetName(), len(f.Metric))
+		}
+	}
+}
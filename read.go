@@ -18,6 +18,7 @@ import "C"
 import (
 	"errors"
 	"syscall"
+	"time"
 	"unsafe"
 )
 
@@ -37,21 +38,61 @@ func srtRecvMsg2Impl(u C.SRTSOCKET, buf []byte, msgctrl *C.SRT_MSGCTRL) (n int,
 }
 
 // Read data from the SRT socket
-func (s SrtSocket) Read(b []byte) (n int, err error) {
+func (s *SrtSocket) Read(b []byte) (n int, err error) {
+	return s.recv(b, nil, nil)
+}
+
+// ReadMsg - Read a message from the SRT socket, along with its message control
+// information: message number, sequence number of its first packet, source
+// time, and the number of messages dropped before it.
+// ReadMsg keeps track of the last message number to report drops, so it must
+// not be mixed with Read or called concurrently on the same socket.
+func (s *SrtSocket) ReadMsg(b []byte) (int, MsgCtrl, error) {
+	mctrl := C.srt_msgctrl_default
+	n, err := s.recv(b, &mctrl, nil)
+	if err != nil {
+		return n, MsgCtrl{}, err
+	}
+
+	msg := MsgCtrl{
+		TTL:      time.Duration(mctrl.msgttl) * time.Millisecond,
+		InOrder:  mctrl.inorder != 0,
+		Boundary: int(mctrl.boundary),
+		SrcTime:  int64(mctrl.srctime),
+		PktSeq:   int32(mctrl.pktseq),
+		MsgNo:    int32(mctrl.msgno),
+	}
+	if s.lastMsgNo > 0 && msg.MsgNo > 0 {
+		msg.Dropped = msgNoDropped(s.lastMsgNo, msg.MsgNo)
+	}
+	s.lastMsgNo = msg.MsgNo
+	return n, msg, nil
+}
+
+// recv - Receive a message, giving up when done is closed
+func (s *SrtSocket) recv(b []byte, mctrl *C.SRT_MSGCTRL, done <-chan struct{}) (int, error) {
+	if s.isClosed() {
+		return 0, &SrtSocketClosed{}
+	}
+	n, err := s.recvWait(b, mctrl, done)
+	return n, s.closedError(err)
+}
+
+func (s *SrtSocket) recvWait(b []byte, mctrl *C.SRT_MSGCTRL, done <-chan struct{}) (n int, err error) {
 	//Fastpath
 	if !s.blocking {
 		s.pd.reset(ModeRead)
 	}
-	n, err = srtRecvMsg2Impl(s.socket, b, nil)
+	n, err = srtRecvMsg2Impl(s.socket, b, mctrl)
 
 	for {
 		if !errors.Is(err, error(EAsyncRCV)) || s.blocking {
 			return
 		}
-		err = s.pd.wait(ModeRead)
+		err = s.pd.waitDone(ModeRead, done)
 		if err != nil {
 			return
 		}
-		n, err = srtRecvMsg2Impl(s.socket, b, nil)
+		n, err = srtRecvMsg2Impl(s.socket, b, mctrl)
 	}
 }
@@ -0,0 +1,408 @@
+package srtgo
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ConnState - State of a ReconnectingConn
+type ConnState int
+
+// ReconnectingConn states
+const (
+	ConnStateConnecting   ConnState = iota // dialing the listener
+	ConnStateConnected                     // connected, Read and Write go through
+	ConnStateDisconnected                  // the connection was lost or the dial failed, waiting before the next one
+	ConnStateClosed                        // Close was called
+)
+
+func (s ConnState) String() string {
+	switch s {
+	case ConnStateConnecting:
+		return "connecting"
+	case ConnStateConnected:
+		return "connected"
+	case ConnStateDisconnected:
+		return "disconnected"
+	case ConnStateClosed:
+		return "closed"
+	}
+	return "ConnState(" + strconv.Itoa(int(s)) + ")"
+}
+
+// Errors returned by ReconnectingConn
+var (
+	ErrConnClosed      = errors.New("srtgo: connection closed")
+	ErrWriteBufferFull = errors.New("srtgo: write buffer full while reconnecting")
+)
+
+// Default backoff of ReconnectOptions
+const (
+	DefaultMinBackoff = 250 * time.Millisecond
+	DefaultMaxBackoff = 30 * time.Second
+	DefaultJitter     = 0.2
+)
+
+// ReconnectOptions - Settings of a ReconnectingConn
+type ReconnectOptions struct {
+	// Delay before the first attempt to redial, doubled after every failed
+	// attempt. DefaultMinBackoff when zero.
+	MinBackoff time.Duration
+	// Maximum delay between two attempts. DefaultMaxBackoff when zero.
+	MaxBackoff time.Duration
+	// Randomization of the delays, as a fraction of them: a delay d becomes a
+	// random delay in [d*(1-Jitter), d*(1+Jitter)]. DefaultJitter when zero,
+	// negative to disable.
+	Jitter float64
+	// Number of bytes of writes buffered while reconnecting, and sent once
+	// connected again. With zero, Write waits for the connection to come back.
+	WriteBufferSize int
+	// Called on every state change, with the error that caused it if any.
+	// Calls are made from a single goroutine, in order.
+	OnStateChange func(state ConnState, err error)
+}
+
+// ReconnectingConn - Caller connection redialing its listener, with
+// exponential backoff, whenever the connection is lost.
+//
+// Every connection is made with the options given to DialReconnecting; options
+// set on the socket afterwards with SetSockOpt are not replayed. Read and
+// Write wait for the connection while it is reestablished, see
+// ReconnectOptions.WriteBufferSize to buffer writes instead.
+type ReconnectingConn struct {
+	host    string
+	port    uint16
+	options map[string]string
+	config  ReconnectOptions
+
+	mu          sync.Mutex
+	socket      *SrtSocket
+	state       ConnState
+	changed     chan struct{}
+	pending     [][]byte
+	pendingSize int
+
+	lost   chan *SrtSocket
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// DialReconnecting connects to the SRT listener at address ("host:port") in
+// caller mode, and keeps the connection up until Close is called. The options
+// are the same as the ones accepted by NewSrtSocket, "mode" is always forced to
+// caller. Invalid options are reported like NewSrtSocketE does. An error is
+// returned if the first connection fails.
+func DialReconnecting(address string, options map[string]string, config ReconnectOptions) (*ReconnectingConn, error) {
+	host, port, err := splitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	if config.MinBackoff <= 0 {
+		config.MinBackoff = DefaultMinBackoff
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = DefaultMaxBackoff
+	}
+	if config.MaxBackoff < config.MinBackoff {
+		config.MaxBackoff = config.MinBackoff
+	}
+	if config.Jitter == 0 {
+		config.Jitter = DefaultJitter
+	}
+
+	opts := copyOptions(options)
+	opts["mode"] = "caller"
+	c := &ReconnectingConn{
+		host:    host,
+		port:    port,
+		options: opts,
+		config:  config,
+		changed: make(chan struct{}),
+		lost:    make(chan *SrtSocket, 1),
+		done:    make(chan struct{}),
+	}
+	c.ctx, c.cancel = context.WithCancel(context.Background())
+
+	c.notify(ConnStateConnecting, nil)
+	s, err := c.dial()
+	if err != nil {
+		c.cancel()
+		return nil, err
+	}
+	c.mu.Lock()
+	c.socket = s
+	c.setState(ConnStateConnected)
+	c.mu.Unlock()
+	c.notify(ConnStateConnected, nil)
+
+	go c.run()
+	return c, nil
+}
+
+func (c *ReconnectingConn) dial() (*SrtSocket, error) {
+	s, err := NewSrtSocketE(c.host, c.port, c.options)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ConnectContext(c.ctx); err != nil {
+		s.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// setState - Change the state and wake up the goroutines waiting for it.
+// Once closed, the state does not change anymore. Must be called with c.mu held.
+func (c *ReconnectingConn) setState(state ConnState) {
+	if c.state == ConnStateClosed {
+		return
+	}
+	c.state = state
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+func (c *ReconnectingConn) notify(state ConnState, err error) {
+	if c.config.OnStateChange != nil {
+		c.config.OnStateChange(state, err)
+	}
+}
+
+// backoff - Return the delay before the attempt following the given number of
+// failed ones
+func (c *ReconnectingConn) backoff(failures int) time.Duration {
+	d := c.config.MaxBackoff
+	if failures < 32 {
+		if b := c.config.MinBackoff << uint(failures); b > 0 && b < d {
+			d = b
+		}
+	}
+	if c.config.Jitter > 0 {
+		d = time.Duration(float64(d) * (1 + c.config.Jitter*(2*rand.Float64()-1)))
+	}
+	return d
+}
+
+// run - Reconnect every time a connection is lost, until Close
+func (c *ReconnectingConn) run() {
+	defer close(c.done)
+	for {
+		var cause error
+		select {
+		case <-c.ctx.Done():
+			return
+		case s := <-c.lost:
+			s.Close()
+			cause = EConnLost
+		}
+		c.notify(ConnStateDisconnected, cause)
+
+		for failures := 0; ; failures++ {
+			select {
+			case <-c.ctx.Done():
+				return
+			case <-time.After(c.backoff(failures)):
+			}
+
+			c.mu.Lock()
+			c.setState(ConnStateConnecting)
+			c.mu.Unlock()
+			c.notify(ConnStateConnecting, nil)
+
+			s, err := c.dial()
+			if err == nil {
+				err = c.connected(s)
+			}
+			if err == nil {
+				c.notify(ConnStateConnected, nil)
+				break
+			}
+			if c.ctx.Err() != nil {
+				return
+			}
+			c.mu.Lock()
+			c.setState(ConnStateDisconnected)
+			c.mu.Unlock()
+			c.notify(ConnStateDisconnected, err)
+		}
+	}
+}
+
+// connected - Send the buffered writes on the new connection, and make it the
+// current one. The writes are sent without holding c.mu, so that Write keeps
+// buffering meanwhile; the ones buffered during the flush are sent in turn.
+func (c *ReconnectingConn) connected(s *SrtSocket) error {
+	sent := 0
+	for {
+		c.mu.Lock()
+		if c.state == ConnStateClosed {
+			c.mu.Unlock()
+			s.Close()
+			return ErrConnClosed
+		}
+		c.pendingSize -= sent
+		pending := c.pending
+		if len(pending) == 0 {
+			c.socket = s
+			c.setState(ConnStateConnected)
+			c.mu.Unlock()
+			return nil
+		}
+		c.pending = nil
+		c.mu.Unlock()
+
+		sent = 0
+		for i, b := range pending {
+			if _, err := s.Write(b); err != nil {
+				s.Close()
+				c.mu.Lock()
+				if c.state != ConnStateClosed {
+					//Keep the unsent writes, in order, for the next connection
+					c.pending = append(pending[i:], c.pending...)
+					c.pendingSize -= sent
+				}
+				c.mu.Unlock()
+				return err
+			}
+			sent += len(b)
+		}
+	}
+}
+
+// connectionLost - Return whether err means the connection has to be redialed
+func connectionLost(err error) bool {
+	var closed *SrtSocketClosed
+	return errors.Is(err, EConnLost) || errors.Is(err, ENoConn) || errors.As(err, &closed)
+}
+
+// lose - Report the loss of the connection of s. The state switches to
+// disconnected right away, so that Read and Write wait for the next connection
+// instead of retrying on s.
+func (c *ReconnectingConn) lose(s *SrtSocket) {
+	c.mu.Lock()
+	if s != c.socket {
+		//Already reported, or closed
+		c.mu.Unlock()
+		return
+	}
+	c.socket = nil
+	c.setState(ConnStateDisconnected)
+	c.mu.Unlock()
+	//c.socket stays nil until run has received s, so this never blocks
+	c.lost <- s
+}
+
+// wait - Return the current socket, waiting for the connection if needed
+func (c *ReconnectingConn) wait() (*SrtSocket, error) {
+	for {
+		c.mu.Lock()
+		switch c.state {
+		case ConnStateClosed:
+			c.mu.Unlock()
+			return nil, ErrConnClosed
+		case ConnStateConnected:
+			s := c.socket
+			c.mu.Unlock()
+			return s, nil
+		}
+		changed := c.changed
+		c.mu.Unlock()
+		<-changed
+	}
+}
+
+// Read data from the connection, waiting for it to be reestablished when it
+// is lost
+func (c *ReconnectingConn) Read(b []byte) (int, error) {
+	for {
+		s, err := c.wait()
+		if err != nil {
+			return 0, err
+		}
+		n, err := s.Read(b)
+		if err == nil || !connectionLost(err) {
+			return n, err
+		}
+		c.lose(s)
+	}
+}
+
+// Write data to the connection. While the connection is reestablished, the
+// data is buffered up to ReconnectOptions.WriteBufferSize bytes, after which
+// ErrWriteBufferFull is returned, or Write waits when there is no buffer.
+func (c *ReconnectingConn) Write(b []byte) (int, error) {
+	for {
+		c.mu.Lock()
+		switch c.state {
+		case ConnStateClosed:
+			c.mu.Unlock()
+			return 0, ErrConnClosed
+		case ConnStateConnected:
+			s := c.socket
+			c.mu.Unlock()
+			n, err := s.Write(b)
+			if err == nil || !connectionLost(err) {
+				return n, err
+			}
+			c.lose(s)
+			continue
+		}
+
+		if c.config.WriteBufferSize > 0 {
+			defer c.mu.Unlock()
+			if c.pendingSize+len(b) > c.config.WriteBufferSize {
+				return 0, ErrWriteBufferFull
+			}
+			c.pending = append(c.pending, append([]byte(nil), b...))
+			c.pendingSize += len(b)
+			return len(b), nil
+		}
+		changed := c.changed
+		c.mu.Unlock()
+		<-changed
+	}
+}
+
+// State - Return the state of the connection
+func (c *ReconnectingConn) State() ConnState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.state
+}
+
+// Socket - Return the socket of the current connection, nil while reconnecting
+func (c *ReconnectingConn) Socket() *SrtSocket {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.socket
+}
+
+// Close the connection and stop reconnecting. Only the first call closes the
+// connection, any later call returns an error.
+func (c *ReconnectingConn) Close() error {
+	c.mu.Lock()
+	if c.state == ConnStateClosed {
+		c.mu.Unlock()
+		return ErrConnClosed
+	}
+	s := c.socket
+	c.socket = nil
+	c.pending = nil
+	c.pendingSize = 0
+	c.state = ConnStateClosed
+	close(c.changed)
+	c.mu.Unlock()
+
+	c.cancel()
+	if s != nil {
+		s.Close()
+	}
+	<-c.done
+	c.notify(ConnStateClosed, nil)
+	return nil
+}
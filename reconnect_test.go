@@ -0,0 +1,168 @@
+package srtgo
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReconnectBackoff(t *testing.T) {
+	c := &ReconnectingConn{config: ReconnectOptions{
+		MinBackoff: 100 * time.Millisecond,
+		MaxBackoff: time.Second,
+		Jitter:     -1,
+	}}
+	for failures, expected := range []time.Duration{
+		100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second,
+	} {
+		if d := c.backoff(failures); d != expected {
+			t.Errorf("after %d failures: got %v, expected %v", failures, d, expected)
+		}
+	}
+	if d := c.backoff(1000); d != time.Second {
+		t.Errorf("after 1000 failures: got %v, expected %v", d, time.Second)
+	}
+
+	c.config.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if d := c.backoff(0); d < 50*time.Millisecond || d > 150*time.Millisecond {
+			t.Fatalf("jittered backoff %v out of range", d)
+		}
+	}
+}
+
+func TestReconnectLose(t *testing.T) {
+	s := &SrtSocket{}
+	c := &ReconnectingConn{
+		socket:  s,
+		state:   ConnStateConnected,
+		changed: make(chan struct{}),
+		lost:    make(chan *SrtSocket, 1),
+	}
+	changed := c.changed
+	c.lose(s)
+	if st := c.State(); st != ConnStateDisconnected {
+		t.Errorf("state after a loss: %v, expected %v", st, ConnStateDisconnected)
+	}
+	if c.Socket() != nil {
+		t.Error("the lost socket is still the current one")
+	}
+	select {
+	case <-changed:
+	default:
+		t.Error("waiters were not woken up by the loss")
+	}
+	//A second report of the same loss is ignored
+	c.lose(s)
+	if lost := <-c.lost; lost != s {
+		t.Error("the lost socket was not handed to the reconnection")
+	}
+	select {
+	case <-c.lost:
+		t.Error("the loss was reported twice")
+	default:
+	}
+}
+
+func TestReconnectingConn(t *testing.T) {
+	InitSRT()
+
+	port := randomPort()
+	ln := NewSrtSocket("127.0.0.1", port, map[string]string{"blocking": "0", "transtype": "live"})
+	if ln == nil {
+		t.Fatal("Could not create a srt socket")
+	}
+	defer ln.Close()
+	if err := ln.Listen(2); err != nil {
+		t.Fatal(err)
+	}
+	accepted := make(chan *SrtSocket, 2)
+	go func() {
+		for {
+			s, _, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			accepted <- s
+		}
+	}()
+
+	var mu sync.Mutex
+	var states []ConnState
+	c, err := DialReconnecting("127.0.0.1:"+strconv.Itoa(int(port)), map[string]string{"blocking": "0", "transtype": "live"}, ReconnectOptions{
+		MinBackoff:      50 * time.Millisecond,
+		WriteBufferSize: 1500,
+		OnStateChange: func(state ConnState, err error) {
+			mu.Lock()
+			states = append(states, state)
+			mu.Unlock()
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	//Drop the first connection, the caller notices it on its next read
+	first := <-accepted
+	first.Close()
+	go func() {
+		buf := make([]byte, 1500)
+		c.Read(buf)
+	}()
+
+	var second *SrtSocket
+	select {
+	case second = <-accepted:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the connection was not reestablished")
+	}
+	defer second.Close()
+
+	if _, err := c.Write([]byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	second.SetReadDeadline(time.Now().Add(3 * time.Second))
+	buf := make([]byte, 1500)
+	n, err := second.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(buf[:n]) != "hello" {
+		t.Errorf("read %q, expected %q", buf[:n], "hello")
+	}
+
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if c.State() != ConnStateClosed {
+		t.Errorf("state %s after Close", c.State())
+	}
+	if _, err := c.Write([]byte("hello")); err != ErrConnClosed {
+		t.Errorf("Write after Close returned %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	expected := []ConnState{ConnStateConnecting, ConnStateConnected, ConnStateDisconnected, ConnStateConnecting, ConnStateConnected, ConnStateClosed}
+	if len(states) != len(expected) {
+		t.Fatalf("got states %v, expected %v", states, expected)
+	}
+	for i := range states {
+		if states[i] != expected[i] {
+			t.Fatalf("got states %v, expected %v", states, expected)
+		}
+	}
+}
+
+func TestDialReconnectingInvalidOptions(t *testing.T) {
+	InitSRT()
+
+	address := "127.0.0.1:" + strconv.Itoa(int(randomPort()))
+	var cerr ConfigError
+	if _, err := DialReconnecting(address, map[string]string{"blocking": "0", "latency": "200ms"}, ReconnectOptions{}); !errors.As(err, &cerr) {
+		t.Errorf("expected a ConfigError, got %v", err)
+	}
+}
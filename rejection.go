@@ -0,0 +1,93 @@
+package srtgo
+
+// #cgo LDFLAGS: -lsrt
+// #include <srt/srt.h>
+import "C"
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Rejection reasons set by libsrt itself, below RejectionReasonPredefined
+const (
+	RejectionReasonUnknown    = int(C.SRT_REJ_UNKNOWN)    // initial value, the connection was not rejected
+	RejectionReasonSystem     = int(C.SRT_REJ_SYSTEM)     // a system function reported a failure
+	RejectionReasonPeer       = int(C.SRT_REJ_PEER)       // the peer rejected the connection for an unknown reason
+	RejectionReasonResource   = int(C.SRT_REJ_RESOURCE)   // a problem with resource allocation, usually memory
+	RejectionReasonRogue      = int(C.SRT_REJ_ROGUE)      // incorrect data in the handshake messages
+	RejectionReasonBacklog    = int(C.SRT_REJ_BACKLOG)    // the listener's backlog exceeded
+	RejectionReasonIPE        = int(C.SRT_REJ_IPE)        // internal program error
+	RejectionReasonClose      = int(C.SRT_REJ_CLOSE)      // the socket is closing
+	RejectionReasonVersion    = int(C.SRT_REJ_VERSION)    // the peer is older than the "minversion" option
+	RejectionReasonRdvCookie  = int(C.SRT_REJ_RDVCOOKIE)  // rendezvous cookie collision
+	RejectionReasonBadSecret  = int(C.SRT_REJ_BADSECRET)  // wrong passphrase
+	RejectionReasonUnsecure   = int(C.SRT_REJ_UNSECURE)   // password required or unexpected
+	RejectionReasonMessageAPI = int(C.SRT_REJ_MESSAGEAPI) // the "messageapi" option differs from the peer
+	RejectionReasonCongestion = int(C.SRT_REJ_CONGESTION) // the "congestion" option differs from the peer
+	RejectionReasonFilter     = int(C.SRT_REJ_FILTER)     // the "packetfilter" option differs from the peer
+	// Reasons added after SRT 1.4.1, defined by value
+	RejectionReasonGroup   = 15 // the group type or group settings differ from the peer
+	RejectionReasonTimeout = 16 // the connection timed out
+	RejectionReasonCrypto  = 17 // the "cryptomode" option differs from the peer
+)
+
+// Descriptions of the predefined rejection reasons, by offset from RejectionReasonPredefined
+var predefinedRejectionText = map[int]string{
+	400: "Bad request",
+	401: "Unauthorized",
+	402: "Server overloaded or credits exceeded",
+	403: "Forbidden",
+	404: "Resource not found",
+	405: "Mode not supported",
+	406: "Parameters not acceptable",
+}
+
+// RejectionError - Error returned by Connect when the connection is rejected
+type RejectionError struct {
+	Reason int    // rejection reason, one of the RejectionReason values or a user-defined one
+	Text   string // description of the reason
+	err    error
+}
+
+func (e *RejectionError) Error() string {
+	return fmt.Sprintf("connection rejected: %s (reason %d)", e.Text, e.Reason)
+}
+
+// Unwrap - Return the underlying error, EConnRej or ENoServer
+func (e *RejectionError) Unwrap() error {
+	return e.err
+}
+
+// RejectionReasonText - Return a description of a rejection reason
+func RejectionReasonText(reason int) string {
+	switch {
+	case reason >= RejectionReasonUserDefined:
+		return fmt.Sprintf("Application-defined rejection reason %d", reason-RejectionReasonUserDefined)
+	case reason >= RejectionReasonPredefined:
+		if text, ok := predefinedRejectionText[reason-RejectionReasonPredefined]; ok {
+			return text
+		}
+		return fmt.Sprintf("Predefined rejection reason %d", reason-RejectionReasonPredefined)
+	}
+	return C.GoString(C.srt_rejectreason_str(C.int(reason)))
+}
+
+// rejectionError - Turn err, returned while connecting, into a RejectionError
+// when the socket has a rejection reason
+func (s *SrtSocket) rejectionError(err error) error {
+	reason := int(C.srt_getrejectreason(s.socket))
+	if reason == RejectionReasonUnknown {
+		return err
+	}
+	//Non-blocking sockets only see an epoll error, use the error a blocking
+	//connect would have returned
+	var srtErr SRTErrno
+	if !errors.As(err, &srtErr) {
+		err = EConnRej
+		if reason == RejectionReasonTimeout {
+			err = ENoServer
+		}
+	}
+	return &RejectionError{Reason: reason, Text: RejectionReasonText(reason), err: err}
+}
@@ -0,0 +1,72 @@
+package srtgo
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+)
+
+func TestRejectionReasonText(t *testing.T) {
+	for reason, expected := range map[int]string{
+		RejectionReasonUnauthorized:       "Unauthorized",
+		RejectionReasonPredefined + 499:   "Predefined rejection reason 499",
+		RejectionReasonUserDefined + 42:   "Application-defined rejection reason 42",
+		RejectionReasonUserDefined + 1000: "Application-defined rejection reason 1000",
+	} {
+		if text := RejectionReasonText(reason); text != expected {
+			t.Errorf("reason %d: got %q, expected %q", reason, text, expected)
+		}
+	}
+}
+
+func TestRejectionError(t *testing.T) {
+	err := fmt.Errorf("dial: %w", &RejectionError{Reason: RejectionReasonNotFound, Text: "Resource not found", err: EConnRej})
+
+	var rejection *RejectionError
+	if !errors.As(err, &rejection) {
+		t.Fatal("errors.As did not find the RejectionError")
+	}
+	if rejection.Reason != RejectionReasonNotFound {
+		t.Errorf("got reason %d, expected %d", rejection.Reason, RejectionReasonNotFound)
+	}
+	if !errors.Is(err, EConnRej) {
+		t.Error("RejectionError does not wrap EConnRej")
+	}
+}
+
+func TestConnectRejected(t *testing.T) {
+	InitSRT()
+
+	port := randomPort()
+	ln := NewSrtSocket("127.0.0.1", port, map[string]string{"blocking": "0", "transtype": "live"})
+	if ln == nil {
+		t.Fatal("Could not create a srt socket")
+	}
+	defer ln.Close()
+	ln.SetListenCallback(func(socket *SrtSocket, version int, addr *net.UDPAddr, streamid string) bool {
+		socket.SetRejectReason(RejectionReasonUserDefined + 7)
+		return false
+	})
+	if err := ln.Listen(1); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, blocking := range []string{"0", "1"} {
+		s := NewSrtSocket("127.0.0.1", port, map[string]string{"blocking": blocking, "transtype": "live", "mode": "caller"})
+		if s == nil {
+			t.Fatal("Could not create a srt socket")
+		}
+		err := s.Connect()
+		s.Close()
+
+		var rejection *RejectionError
+		if !errors.As(err, &rejection) {
+			t.Errorf("blocking=%s: expected a RejectionError, got %v", blocking, err)
+			continue
+		}
+		if rejection.Reason != RejectionReasonUserDefined+7 {
+			t.Errorf("blocking=%s: got reason %d, expected %d", blocking, rejection.Reason, RejectionReasonUserDefined+7)
+		}
+	}
+}
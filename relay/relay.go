@@ -0,0 +1,397 @@
+// Package relay fans out SRT streams: publishers send a stream to the relay,
+// which forwards every packet of it to all the subscribers of that stream.
+//
+// Publishers and subscribers connect to the same listener, with a stream ID in
+// the SRT access control syntax (see srtgo.ParseStreamID): the resource ("r")
+// names the stream, and the mode ("m") is publish for publishers and request
+// (or nothing) for subscribers. Subscribers may connect before the publisher
+// of their stream, and stay connected when it goes away.
+package relay
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/haivision/srtgo"
+)
+
+// ErrRelayClosed is returned by Relay.ListenAndServe after Close
+var ErrRelayClosed = errors.New("relay: Relay closed")
+
+// Defaults of the Relay settings
+const (
+	DefaultQueueSize = 256
+	maxPacketSize    = 1500
+)
+
+// DropPolicy - What to do with a packet for a subscriber whose queue is full
+type DropPolicy int
+
+// Drop policies
+const (
+	// Discard the oldest queued packet to make room for the new one
+	DropOldest DropPolicy = iota
+	// Discard the new packet
+	DropNewest
+	// Disconnect the subscriber
+	Disconnect
+)
+
+func (p DropPolicy) String() string {
+	switch p {
+	case DropOldest:
+		return "drop-oldest"
+	case DropNewest:
+		return "drop-newest"
+	case Disconnect:
+		return "disconnect"
+	}
+	return "DropPolicy(" + strconv.Itoa(int(p)) + ")"
+}
+
+// Relay - SRT relay server, built on srtgo.Server: accept errors are retried
+// the way Server does
+type Relay struct {
+	// Address to listen on, "host:port" (host may be empty to listen on all interfaces)
+	Addr string
+	// Options of the listening socket, as accepted by srtgo.NewSrtSocket. The
+	// socket is always a non-blocking listener.
+	Options map[string]string
+	// Number of packets queued per subscriber, DefaultQueueSize when 0
+	QueueSize int
+	// What to do when the queue of a subscriber is full
+	DropPolicy DropPolicy
+	// Logger for accept errors, the standard logger when nil
+	ErrorLog *log.Logger
+
+	mu      sync.Mutex
+	streams map[string]*stream
+	srv     *srtgo.Server
+	closed  bool
+}
+
+type stream struct {
+	name      string
+	publisher *srtgo.Conn
+	// []*subscriber, replaced as a whole under Relay.mu so that the publisher
+	// reads it without locking
+	subscribers atomic.Value
+}
+
+type subscriber struct {
+	conn      *srtgo.Conn
+	queue     chan []byte
+	done      chan struct{}
+	closeOnce sync.Once
+	dropped   uint64
+}
+
+// StreamStats - Status of a stream of the relay
+type StreamStats struct {
+	Name        string
+	Published   bool   // whether a publisher is connected
+	Subscribers int    // number of connected subscribers
+	Dropped     uint64 // packets dropped for the connected subscribers
+}
+
+// ListenAndServe - Listen on Addr and relay the streams until Close is
+// called, in which case ErrRelayClosed is returned
+func (r *Relay) ListenAndServe() error {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return ErrRelayClosed
+	}
+	srv := &srtgo.Server{
+		Addr:     r.Addr,
+		Options:  r.Options,
+		ErrorLog: r.ErrorLog,
+		Admit:    r.admit,
+	}
+	srv.Handle("*", r.serve)
+	r.srv = srv
+	r.mu.Unlock()
+
+	err := srv.ListenAndServe()
+	if errors.Is(err, srtgo.ErrServerClosed) {
+		return ErrRelayClosed
+	}
+	return err
+}
+
+// parseStreamID - Return the stream name and whether the caller publishes
+func parseStreamID(streamid string) (string, bool, error) {
+	sid, err := srtgo.ParseStreamID(streamid)
+	if err != nil {
+		return "", false, err
+	}
+	if sid.Resource == "" {
+		return "", false, &srtgo.StreamIDError{StreamID: streamid, Reason: srtgo.RejectionReasonBadRequest, Msg: "missing resource"}
+	}
+	switch sid.Mode {
+	case srtgo.StreamIDModePublish:
+		return sid.Resource, true, nil
+	case srtgo.StreamIDModeRequest, "":
+		return sid.Resource, false, nil
+	}
+	return "", false, &srtgo.StreamIDError{StreamID: streamid, Reason: srtgo.RejectionReasonBadMode, Msg: "mode " + string(sid.Mode) + " not supported by the relay"}
+}
+
+// admit - Reject the callers with an invalid stream ID, and the publishers of
+// a stream that already has one
+func (r *Relay) admit(req *srtgo.Request) error {
+	name, publish, err := parseStreamID(req.StreamID)
+	if err != nil {
+		return err
+	}
+	if publish && r.published(name) {
+		return &srtgo.StreamIDError{StreamID: req.StreamID, Reason: srtgo.RejectionReasonForbidden, Msg: "stream " + name + " already published"}
+	}
+	return nil
+}
+
+func (r *Relay) published(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	st, ok := r.streams[name]
+	return ok && st.publisher != nil
+}
+
+// stream - Return the stream with the given name, creating it if needed.
+// Must be called with r.mu held.
+func (r *Relay) stream(name string) *stream {
+	st, ok := r.streams[name]
+	if !ok {
+		st = &stream{name: name}
+		st.subscribers.Store([]*subscriber(nil))
+		if r.streams == nil {
+			r.streams = make(map[string]*stream)
+		}
+		r.streams[name] = st
+	}
+	return st
+}
+
+// release - Forget the stream when nobody uses it anymore. Must be called
+// with r.mu held.
+func (r *Relay) release(st *stream) {
+	if st.publisher == nil && len(st.subs()) == 0 {
+		delete(r.streams, st.name)
+	}
+}
+
+// subs - Return the current subscribers of the stream, never modified
+func (st *stream) subs() []*subscriber {
+	return st.subscribers.Load().([]*subscriber)
+}
+
+// addSubscriber - Must be called with Relay.mu held
+func (st *stream) addSubscriber(sub *subscriber) {
+	old := st.subs()
+	subs := make([]*subscriber, len(old), len(old)+1)
+	copy(subs, old)
+	st.subscribers.Store(append(subs, sub))
+}
+
+// removeSubscriber - Must be called with Relay.mu held
+func (st *stream) removeSubscriber(sub *subscriber) {
+	old := st.subs()
+	subs := make([]*subscriber, 0, len(old))
+	for _, s := range old {
+		if s != sub {
+			subs = append(subs, s)
+		}
+	}
+	st.subscribers.Store(subs)
+}
+
+// serve - Relay for a connection admitted by the server, which closes it on
+// return
+func (r *Relay) serve(conn *srtgo.Conn, req *srtgo.Request) {
+	name, publish, err := parseStreamID(req.StreamID)
+	if err != nil {
+		return
+	}
+	if publish {
+		r.publish(name, conn)
+	} else {
+		r.subscribe(name, conn)
+	}
+}
+
+// publish - Read the packets of a publisher and hand them to the subscribers
+func (r *Relay) publish(name string, conn *srtgo.Conn) {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return
+	}
+	st := r.stream(name)
+	//Two publishers may pass the admission check at the same time
+	if st.publisher != nil {
+		r.mu.Unlock()
+		return
+	}
+	st.publisher = conn
+	r.mu.Unlock()
+
+	defer func() {
+		r.mu.Lock()
+		st.publisher = nil
+		r.release(st)
+		r.mu.Unlock()
+	}()
+
+	buf := make([]byte, maxPacketSize)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return
+		}
+		if n == 0 {
+			continue
+		}
+		//Shared by all the subscribers, never modified
+		pkt := make([]byte, n)
+		copy(pkt, buf[:n])
+
+		for _, sub := range st.subs() {
+			sub.deliver(pkt, r.DropPolicy)
+		}
+	}
+}
+
+// deliver - Queue a packet for the subscriber, without blocking
+func (sub *subscriber) deliver(pkt []byte, policy DropPolicy) {
+	select {
+	case sub.queue <- pkt:
+		return
+	default:
+	}
+
+	atomic.AddUint64(&sub.dropped, 1)
+	switch policy {
+	case DropOldest:
+		select {
+		case <-sub.queue:
+		default:
+		}
+		select {
+		case sub.queue <- pkt:
+		default:
+		}
+	case Disconnect:
+		sub.close()
+	}
+}
+
+func (sub *subscriber) close() {
+	sub.closeOnce.Do(func() {
+		close(sub.done)
+	})
+}
+
+// subscribe - Write the queued packets to a subscriber
+func (r *Relay) subscribe(name string, conn *srtgo.Conn) {
+	queueSize := r.QueueSize
+	if queueSize <= 0 {
+		queueSize = DefaultQueueSize
+	}
+	sub := &subscriber{
+		conn:  conn,
+		queue: make(chan []byte, queueSize),
+		done:  make(chan struct{}),
+	}
+
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return
+	}
+	st := r.stream(name)
+	st.addSubscriber(sub)
+	r.mu.Unlock()
+
+	defer func() {
+		r.mu.Lock()
+		st.removeSubscriber(sub)
+		r.release(st)
+		r.mu.Unlock()
+	}()
+
+	for {
+		select {
+		case pkt := <-sub.queue:
+			if _, err := conn.Write(pkt); err != nil {
+				return
+			}
+		case <-sub.done:
+			return
+		}
+	}
+}
+
+// Streams - Return the status of the streams with a publisher or subscribers,
+// sorted by name
+func (r *Relay) Streams() []StreamStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stats := make([]StreamStats, 0, len(r.streams))
+	for _, st := range r.streams {
+		subs := st.subs()
+		s := StreamStats{
+			Name:        st.name,
+			Published:   st.publisher != nil,
+			Subscribers: len(subs),
+		}
+		for _, sub := range subs {
+			s.Dropped += atomic.LoadUint64(&sub.dropped)
+		}
+		stats = append(stats, s)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Name < stats[j].Name })
+	return stats
+}
+
+// Counts - Return the number of connected publishers and subscribers.
+// Subscribers that went away are only noticed on the next packet relayed to them.
+func (r *Relay) Counts() (publishers, subscribers int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, st := range r.streams {
+		if st.publisher != nil {
+			publishers++
+		}
+		subscribers += len(st.subs())
+	}
+	return
+}
+
+// Close - Stop accepting connections, disconnect the publishers and
+// subscribers, and wait for them to be released
+func (r *Relay) Close() error {
+	r.mu.Lock()
+	r.closed = true
+	srv := r.srv
+	var subs []*subscriber
+	for _, st := range r.streams {
+		subs = append(subs, st.subs()...)
+	}
+	r.mu.Unlock()
+
+	//Stopped without holding r.mu, which the server callbacks take
+	for _, sub := range subs {
+		sub.close()
+	}
+	if srv != nil {
+		srv.Close()
+		//Close interrupted the handlers, wait for them to return
+		srv.Shutdown(context.Background())
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package relay
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/haivision/srtgo"
+)
+
+func randomPort() uint16 {
+	return uint16(rand.Intn(10000) + 20000)
+}
+
+func TestParseStreamID(t *testing.T) {
+	for _, tc := range []struct {
+		streamid string
+		name     string
+		publish  bool
+		reason   int
+	}{
+		{"#!::r=live/cam1,m=publish", "live/cam1", true, 0},
+		{"#!::r=live/cam1,m=request", "live/cam1", false, 0},
+		{"#!::r=live/cam1", "live/cam1", false, 0},
+		{"#!::m=publish", "", false, srtgo.RejectionReasonBadRequest},
+		{"live/cam1", "", false, srtgo.RejectionReasonBadRequest},
+		{"#!::r=live/cam1,m=bidirectional", "", false, srtgo.RejectionReasonBadMode},
+	} {
+		name, publish, err := parseStreamID(tc.streamid)
+		if tc.reason != 0 {
+			if reason := srtgo.StreamIDRejectionReason(err); err == nil || reason != tc.reason {
+				t.Errorf("%s: expected rejection reason %d, got %v", tc.streamid, tc.reason, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tc.streamid, err)
+			continue
+		}
+		if name != tc.name || publish != tc.publish {
+			t.Errorf("%s: got %s (publish %v), expected %s (publish %v)", tc.streamid, name, publish, tc.name, tc.publish)
+		}
+	}
+}
+
+func TestDeliver(t *testing.T) {
+	newSub := func() *subscriber {
+		return &subscriber{queue: make(chan []byte, 2), done: make(chan struct{})}
+	}
+	packets := [][]byte{{1}, {2}, {3}}
+
+	sub := newSub()
+	for _, pkt := range packets {
+		sub.deliver(pkt, DropOldest)
+	}
+	if first := <-sub.queue; first[0] != 2 || sub.dropped != 1 {
+		t.Errorf("DropOldest: first queued packet %d, %d dropped", first[0], sub.dropped)
+	}
+
+	sub = newSub()
+	for _, pkt := range packets {
+		sub.deliver(pkt, DropNewest)
+	}
+	if first := <-sub.queue; first[0] != 1 || sub.dropped != 1 {
+		t.Errorf("DropNewest: first queued packet %d, %d dropped", first[0], sub.dropped)
+	}
+
+	sub = newSub()
+	for _, pkt := range packets {
+		sub.deliver(pkt, Disconnect)
+	}
+	select {
+	case <-sub.done:
+	default:
+		t.Error("Disconnect: subscriber not closed")
+	}
+}
+
+func TestRelay(t *testing.T) {
+	srtgo.InitSRT()
+
+	r := &Relay{
+		Addr:    "127.0.0.1:" + strconv.Itoa(int(randomPort())),
+		Options: map[string]string{"transtype": "live"},
+	}
+	served := make(chan error, 1)
+	go func() {
+		served <- r.ListenAndServe()
+	}()
+	defer r.Close()
+
+	dial := func(streamid string) *srtgo.Conn {
+		t.Helper()
+		var err error
+		for i := 0; i < 10; i++ {
+			var c *srtgo.Conn
+			c, err = srtgo.Dial(r.Addr, map[string]string{"transtype": "live", "blocking": "0", "streamid": streamid})
+			if err == nil {
+				return c
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+		t.Fatal(err)
+		return nil
+	}
+
+	//The resource of the last stream ID comes after the first 256 bytes
+	subs := []*srtgo.Conn{
+		dial("#!::r=live/cam1"),
+		dial("#!::r=live/cam1,m=request"),
+		dial("#!::u=" + strings.Repeat("x", 300) + ",r=live/cam1"),
+	}
+	pub := dial("#!::r=live/cam1,m=publish")
+	defer pub.Close()
+	for _, s := range subs {
+		defer s.Close()
+	}
+
+	if _, err := srtgo.Dial(r.Addr, map[string]string{"transtype": "live", "blocking": "0", "streamid": "#!::r=live/cam1,m=publish"}); err == nil {
+		t.Error("a second publisher connected to the same stream")
+	}
+
+	//Wait for the relay to register everybody
+	for i := 0; i < 20; i++ {
+		if p, s := r.Counts(); p == 1 && s == 3 {
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	if p, s := r.Counts(); p != 1 || s != 3 {
+		t.Fatalf("got %d publishers and %d subscribers, expected 1 and 3", p, s)
+	}
+
+	if _, err := pub.Write([]byte("packet")); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 1500)
+	for i, s := range subs {
+		s.SetReadDeadline(time.Now().Add(3 * time.Second))
+		n, err := s.Read(buf)
+		if err != nil {
+			t.Fatalf("subscriber %d: %v", i, err)
+		}
+		if string(buf[:n]) != "packet" {
+			t.Errorf("subscriber %d read %q", i, buf[:n])
+		}
+	}
+
+	streams := r.Streams()
+	if len(streams) != 1 || streams[0].Name != "live/cam1" || !streams[0].Published || streams[0].Subscribers != 3 {
+		t.Errorf("unexpected streams %+v", streams)
+	}
+
+	r.Close()
+	select {
+	case err := <-served:
+		if err != ErrRelayClosed {
+			t.Errorf("ListenAndServe returned %v, expected ErrRelayClosed", err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Error("ListenAndServe did not return after Close")
+	}
+	if p, s := r.Counts(); p != 0 || s != 0 {
+		t.Errorf("%d publishers and %d subscribers left after Close", p, s)
+	}
+}
+
+func TestStreamSubscribers(t *testing.T) {
+	r := &Relay{}
+	st := r.stream("live/cam1")
+	a, b := &subscriber{}, &subscriber{}
+	st.addSubscriber(a)
+	before := st.subs()
+	st.addSubscriber(b)
+	st.removeSubscriber(a)
+	//The publisher may still range over a previous slice
+	if len(before) != 1 || before[0] != a {
+		t.Errorf("previous subscribers modified: %v", before)
+	}
+	if subs := st.subs(); len(subs) != 1 || subs[0] != b {
+		t.Errorf("unexpected subscribers %v", subs)
+	}
+	st.removeSubscriber(b)
+	r.release(st)
+	if len(r.streams) != 0 {
+		t.Error("unused stream not released")
+	}
+}
@@ -0,0 +1,103 @@
+package srtgo
+
+import (
+	"bytes"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func rendezvousPair(t *testing.T, blocking string) (*SrtSocket, *SrtSocket) {
+	portA := randomPort()
+	portB := portA + 1
+	a := NewSrtSocket("127.0.0.1", portB, map[string]string{
+		"blocking":  blocking,
+		"mode":      "rendezvous",
+		"adapter":   "127.0.0.1",
+		"localport": strconv.Itoa(int(portA)),
+	})
+	b := NewSrtSocket("127.0.0.1", portA, map[string]string{
+		"blocking":  blocking,
+		"mode":      "rendezvous",
+		"adapter":   "127.0.0.1",
+		"localport": strconv.Itoa(int(portB)),
+	})
+	if a == nil || b == nil {
+		t.Fatal("Could not create rendezvous sockets")
+	}
+	if a.Mode() != ModeRendezvouz || b.Mode() != ModeRendezvouz {
+		t.Fatalf("expected rendezvous mode, got %d and %d", a.Mode(), b.Mode())
+	}
+	return a, b
+}
+
+func testRendezvous(t *testing.T, blocking string) {
+	InitSRT()
+
+	a, b := rendezvousPair(t, blocking)
+	defer a.Close()
+	defer b.Close()
+
+	timer := time.AfterFunc(5*time.Second, func() {
+		t.Log("Rendezvous timed out")
+		a.Close()
+		b.Close()
+	})
+	defer timer.Stop()
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := b.Connect(); err != nil {
+			t.Error(err)
+		}
+	}()
+	if err := a.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	wg.Wait()
+
+	msg := []byte("rendezvous")
+	if _, err := a.Write(msg); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 1500)
+	n, err := b.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf[:n], msg) {
+		t.Errorf("read %q, expected %q", buf[:n], msg)
+	}
+}
+
+func TestRendezvousNonBlocking(t *testing.T) {
+	testRendezvous(t, "0")
+}
+
+func TestRendezvousBlocking(t *testing.T) {
+	testRendezvous(t, "1")
+}
+
+func TestRendezvousModeFromAdapter(t *testing.T) {
+	InitSRT()
+
+	s := NewSrtSocket("127.0.0.1", 8090, map[string]string{"adapter": "127.0.0.1"})
+	if s == nil {
+		t.Fatal("Could not create a srt socket")
+	}
+	defer s.Close()
+
+	if s.Mode() != ModeRendezvouz {
+		t.Errorf("expected rendezvous mode when an adapter is given, got %d", s.Mode())
+	}
+	rendezvous, err := s.GetSockOptBool(SRTO_RENDEZVOUS)
+	if err != nil {
+		t.Error(err)
+	}
+	if !rendezvous {
+		t.Error("SRTO_RENDEZVOUS should be set on a rendezvous socket")
+	}
+}
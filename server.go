@@ -0,0 +1,357 @@
+package srtgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrServerClosed is returned by Server.ListenAndServe after Shutdown or Close
+var ErrServerClosed = errors.New("srtgo: Server closed")
+
+// Request - Connection routed to a HandlerFunc by a Server
+type Request struct {
+	// Stream ID sent by the caller
+	StreamID string
+	// Stream ID parsed from the access control syntax, nil when the stream ID
+	// does not use it
+	ParsedStreamID *StreamID
+	// Pattern of the route that matched the stream ID
+	Pattern string
+	// Address of the caller
+	RemoteAddr *net.UDPAddr
+}
+
+// HandlerFunc - Function serving a connection accepted by a Server. The
+// connection is closed when the function returns.
+type HandlerFunc func(conn *Conn, req *Request)
+
+type route struct {
+	pattern string
+	handler HandlerFunc
+	options map[string]string
+}
+
+// Server - SRT server accepting connections and routing them to handlers by
+// stream ID, in the spirit of net/http.Server.
+//
+// Routes are matched against the resource ("r" key) of stream IDs in the
+// access control syntax (see ParseStreamID), or against the whole stream ID
+// otherwise. A pattern matches that name exactly, unless it ends with "/", in
+// which case it matches every name it is a prefix of; the longest pattern
+// wins. The pattern "*" matches every stream ID. Connections matching no route
+// are rejected with RejectionReasonNotFound.
+type Server struct {
+	// Address to listen on, "host:port" (host may be empty to listen on all interfaces)
+	Addr string
+	// Options of the listening socket, as accepted by NewSrtSocket. The socket
+	// is always a non-blocking listener. Invalid options are reported by
+	// ListenAndServe like NewSrtSocketE does.
+	Options map[string]string
+	// Maximum number of connections served at once, 0 for no limit. Callers
+	// over the limit are rejected with RejectionReasonOverload.
+	MaxConns int
+	// Logger for handler panics and accept errors, the standard logger when nil
+	ErrorLog *log.Logger
+	// Admission check of the routed connections, run while they are accepted.
+	// Callers for which it returns an error are rejected with
+	// StreamIDRejectionReason(err). Nil admits every routed connection.
+	Admit func(req *Request) error
+
+	mu       sync.Mutex
+	routes   []route
+	listener *SrtSocket
+	conns    map[*Conn]struct{}
+	closed   bool
+	handlers sync.WaitGroup
+}
+
+// Handle - Register the handler for connections whose stream ID matches pattern
+func (srv *Server) Handle(pattern string, handler HandlerFunc) {
+	srv.HandleWithOptions(pattern, nil, handler)
+}
+
+// HandleWithOptions - Register the handler for connections whose stream ID
+// matches pattern, setting options on those connections while they are
+// accepted. The options are the ones accepted by SetConnectionOptions.
+func (srv *Server) HandleWithOptions(pattern string, options map[string]string, handler HandlerFunc) {
+	if pattern == "" {
+		panic("srtgo: empty pattern")
+	}
+	if handler == nil {
+		panic("srtgo: nil handler")
+	}
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	for _, r := range srv.routes {
+		if r.pattern == pattern {
+			panic("srtgo: multiple registrations for " + pattern)
+		}
+	}
+	srv.routes = append(srv.routes, route{pattern, handler, copyOptions(options)})
+	//Longest patterns first, the catch-all last
+	sort.SliceStable(srv.routes, func(i, j int) bool {
+		pi, pj := srv.routes[i].pattern, srv.routes[j].pattern
+		if pi == "*" || pj == "*" {
+			return pj == "*" && pi != "*"
+		}
+		return len(pi) > len(pj)
+	})
+}
+
+// match - Return the route for streamid, and the parsed stream ID when it uses
+// the access control syntax
+func (srv *Server) match(streamid string) (*route, *StreamID, error) {
+	name := streamid
+	var sid *StreamID
+	if strings.HasPrefix(streamid, streamIDPrefix) {
+		var err error
+		if sid, err = ParseStreamID(streamid); err != nil {
+			return nil, nil, err
+		}
+		name = sid.Resource
+	}
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	for _, r := range srv.routes {
+		if r.pattern == "*" || r.pattern == name ||
+			strings.HasSuffix(r.pattern, "/") && strings.HasPrefix(name, r.pattern) {
+			return &r, sid, nil
+		}
+	}
+	return nil, sid, nil
+}
+
+// ListenAndServe - Listen on Addr and serve the connections until Shutdown or
+// Close is called, in which case ErrServerClosed is returned.
+//
+// Accept errors are logged and accepting resumes after a delay growing up to
+// one second, unless the listening socket itself is no longer usable, in
+// which case the error is returned.
+func (srv *Server) ListenAndServe() error {
+	host, port, err := splitHostPort(srv.Addr)
+	if err != nil {
+		return err
+	}
+	if host == "" {
+		host = "0.0.0.0"
+	}
+
+	opts := copyOptions(srv.Options)
+	opts["mode"] = "listener"
+	opts["blocking"] = "0"
+	s, err := NewSrtSocketE(host, port, opts)
+	if err != nil {
+		return fmt.Errorf("listen %s: %w", srv.Addr, err)
+	}
+	s.SetListenCallback(srv.listenCallback)
+
+	srv.mu.Lock()
+	if srv.closed {
+		srv.mu.Unlock()
+		s.Close()
+		return ErrServerClosed
+	}
+	srv.listener = s
+	srv.mu.Unlock()
+
+	if err := s.Listen(defaultListenBacklog); err != nil {
+		srv.closeListener()
+		return fmt.Errorf("listen %s: %w", srv.Addr, err)
+	}
+
+	var delay time.Duration
+	for {
+		socket, addr, err := s.Accept()
+		if err != nil {
+			if srv.shuttingDown() {
+				return ErrServerClosed
+			}
+			if listenerFailed(err) {
+				srv.closeListener()
+				return err
+			}
+			if delay == 0 {
+				delay = 5 * time.Millisecond
+			} else {
+				delay *= 2
+			}
+			if delay > time.Second {
+				delay = time.Second
+			}
+			srv.logf("srtgo: accept error: %v; retrying in %v", err, delay)
+			time.Sleep(delay)
+			continue
+		}
+		delay = 0
+		srv.serve(newConn(socket, addr))
+	}
+}
+
+// listenerFailed - Return whether the accept error err means the listening
+// socket cannot accept anymore
+func listenerFailed(err error) bool {
+	var closed *SrtSocketClosed
+	return errors.As(err, &closed) || errors.Is(err, EInvSock) || errors.Is(err, ENoListen)
+}
+
+func (srv *Server) listenCallback(socket *SrtSocket, version int, addr *net.UDPAddr, streamid string) bool {
+	r, sid, err := srv.match(streamid)
+	if err != nil {
+		socket.SetRejectReason(StreamIDRejectionReason(err))
+		return false
+	}
+	if r == nil {
+		socket.SetRejectReason(RejectionReasonNotFound)
+		return false
+	}
+	if srv.overloaded() {
+		socket.SetRejectReason(RejectionReasonOverload)
+		return false
+	}
+	if srv.Admit != nil {
+		req := &Request{StreamID: streamid, ParsedStreamID: sid, Pattern: r.pattern, RemoteAddr: addr}
+		if err := srv.Admit(req); err != nil {
+			socket.SetRejectReason(StreamIDRejectionReason(err))
+			return false
+		}
+	}
+	if len(r.options) > 0 {
+		if err := socket.SetConnectionOptions(r.options); err != nil {
+			srv.logf("srtgo: setting options of %s for %s: %v", r.pattern, addr, err)
+			socket.SetRejectReason(RejectionReasonUnacceptable)
+			return false
+		}
+	}
+	return true
+}
+
+func (srv *Server) overloaded() bool {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	return srv.MaxConns > 0 && len(srv.conns) >= srv.MaxConns
+}
+
+// serve - Start the handler of an accepted connection
+func (srv *Server) serve(conn *Conn) {
+	streamid, err := conn.s.GetSockOptString(SRTO_STREAMID)
+	if err != nil {
+		srv.logf("srtgo: reading the stream ID of %s: %v", conn.raddr, err)
+		conn.Close()
+		return
+	}
+	r, sid, err := srv.match(streamid)
+	if err != nil || r == nil {
+		//The routes are checked in the listen callback, this only happens
+		//when libsrt accepts without calling it
+		conn.Close()
+		return
+	}
+
+	srv.mu.Lock()
+	//The listen callback only approximates the limit, enforce it here
+	if srv.closed || srv.MaxConns > 0 && len(srv.conns) >= srv.MaxConns {
+		srv.mu.Unlock()
+		conn.Close()
+		return
+	}
+	if srv.conns == nil {
+		srv.conns = make(map[*Conn]struct{})
+	}
+	srv.conns[conn] = struct{}{}
+	srv.handlers.Add(1)
+	srv.mu.Unlock()
+
+	req := &Request{
+		StreamID:       streamid,
+		ParsedStreamID: sid,
+		Pattern:        r.pattern,
+		RemoteAddr:     conn.raddr,
+	}
+	go func() {
+		defer srv.handlers.Done()
+		defer func() {
+			if err := recover(); err != nil {
+				buf := make([]byte, 64<<10)
+				buf = buf[:runtime.Stack(buf, false)]
+				srv.logf("srtgo: panic serving %s (%s): %v\n%s", req.RemoteAddr, streamid, err, buf)
+			}
+			conn.Close()
+			srv.mu.Lock()
+			delete(srv.conns, conn)
+			srv.mu.Unlock()
+		}()
+		r.handler(conn, req)
+	}()
+}
+
+func (srv *Server) shuttingDown() bool {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	return srv.closed
+}
+
+// closeListener - Stop accepting connections
+func (srv *Server) closeListener() {
+	srv.mu.Lock()
+	srv.closed = true
+	listener := srv.listener
+	srv.listener = nil
+	srv.mu.Unlock()
+	//Closed without holding srv.mu: libsrt runs the listen callback, which
+	//takes srv.mu, with the listener lock held, and closing needs that lock
+	if listener != nil {
+		listener.Close()
+	}
+}
+
+// Shutdown - Stop accepting connections and wait for the handlers of the
+// active connections to return, or for ctx to be done, in which case ctx.Err()
+// is returned. Shutdown does not interrupt the handlers, use Close for that.
+func (srv *Server) Shutdown(ctx context.Context) error {
+	srv.closeListener()
+
+	done := make(chan struct{})
+	go func() {
+		srv.handlers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close - Stop accepting connections and close the active ones
+func (srv *Server) Close() error {
+	srv.closeListener()
+
+	srv.mu.Lock()
+	conns := make([]*Conn, 0, len(srv.conns))
+	for c := range srv.conns {
+		conns = append(conns, c)
+	}
+	srv.mu.Unlock()
+	for _, c := range conns {
+		c.Close()
+	}
+	return nil
+}
+
+func (srv *Server) logf(format string, args ...interface{}) {
+	if srv.ErrorLog != nil {
+		srv.ErrorLog.Printf(format, args...)
+	} else {
+		log.Printf(format, args...)
+	}
+}
@@ -0,0 +1,247 @@
+package srtgo
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"log"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServerMatch(t *testing.T) {
+	srv := &Server{}
+	handler := func(conn *Conn, req *Request) {}
+	srv.Handle("live/camera1", handler)
+	srv.Handle("live/", handler)
+	srv.Handle("*", handler)
+	srv.Handle("vod/", handler)
+
+	for streamid, pattern := range map[string]string{
+		"live/camera1":                    "live/camera1",
+		"live/camera2":                    "live/",
+		"#!::r=live/camera1,m=publish":    "live/camera1",
+		"#!::r=vod/movie,m=request,u=bob": "vod/",
+		"other":                           "*",
+		"#!::u=bob":                       "*",
+	} {
+		r, _, err := srv.match(streamid)
+		if err != nil {
+			t.Errorf("%s: %v", streamid, err)
+			continue
+		}
+		if r == nil || r.pattern != pattern {
+			t.Errorf("%s: matched %v, expected %s", streamid, r, pattern)
+		}
+	}
+
+	if _, _, err := srv.match("#!::r=live/camera1,m=play"); StreamIDRejectionReason(err) != RejectionReasonBadMode {
+		t.Errorf("expected a bad mode error, got %v", err)
+	}
+
+	srv = &Server{}
+	srv.Handle("live/", handler)
+	if r, _, _ := srv.match("vod/movie"); r != nil {
+		t.Errorf("vod/movie matched %s", r.pattern)
+	}
+}
+
+func TestServer(t *testing.T) {
+	InitSRT()
+
+	port := randomPort()
+	srv := &Server{
+		Addr:     "127.0.0.1:" + strconv.Itoa(int(port)),
+		Options:  map[string]string{"transtype": "live"},
+		ErrorLog: log.New(ioutil.Discard, "", 0),
+	}
+	srv.Handle("echo", func(conn *Conn, req *Request) {
+		buf := make([]byte, 1500)
+		n, err := conn.Read(buf)
+		if err != nil {
+			return
+		}
+		conn.Write(buf[:n])
+	})
+	srv.Handle("panic", func(conn *Conn, req *Request) {
+		panic("handler panic")
+	})
+	srv.Handle("long/", func(conn *Conn, req *Request) {
+		conn.Write([]byte(req.StreamID))
+	})
+
+	served := make(chan error, 1)
+	go func() {
+		served <- srv.ListenAndServe()
+	}()
+
+	dial := func(streamid string) (*Conn, error) {
+		var err error
+		for i := 0; i < 10; i++ {
+			var c *Conn
+			c, err = Dial(srv.Addr, map[string]string{"transtype": "live", "blocking": "0", "streamid": streamid})
+			if err == nil {
+				return c, nil
+			}
+			//The server may not be listening yet
+			time.Sleep(50 * time.Millisecond)
+		}
+		return nil, err
+	}
+
+	c, err := dial("echo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if _, err := c.Write([]byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	c.SetReadDeadline(time.Now().Add(3 * time.Second))
+	buf := make([]byte, 1500)
+	n, err := c.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(buf[:n]) != "hello" {
+		t.Errorf("read %q, expected %q", buf[:n], "hello")
+	}
+
+	//Stream IDs longer than 256 bytes reach the handler whole
+	long := "long/" + strings.Repeat("x", 400)
+	l, err := dial(long)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	l.SetReadDeadline(time.Now().Add(3 * time.Second))
+	if n, err = l.Read(buf); err != nil {
+		t.Fatal(err)
+	}
+	if string(buf[:n]) != long {
+		t.Errorf("handler got a %d bytes stream ID, expected %d", n, len(long))
+	}
+
+	var rejection *RejectionError
+	if _, err := Dial(srv.Addr, map[string]string{"transtype": "live", "blocking": "0", "streamid": "unknown"}); !errors.As(err, &rejection) || rejection.Reason != RejectionReasonNotFound {
+		t.Errorf("expected a not found rejection, got %v", err)
+	}
+
+	//A panicking handler only closes its own connection
+	p, err := dial("panic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-served:
+		if err != ErrServerClosed {
+			t.Errorf("ListenAndServe returned %v, expected ErrServerClosed", err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Error("ListenAndServe did not return after Shutdown")
+	}
+}
+
+func TestServerListenerFailed(t *testing.T) {
+	for _, err := range []error{&SrtSocketClosed{}, EInvSock, ENoListen} {
+		if !listenerFailed(err) {
+			t.Errorf("%v: the listener should be reported as failed", err)
+		}
+	}
+	for _, err := range []error{EConnLost, EResource, &SrtEpollTimeout{}} {
+		if listenerFailed(err) {
+			t.Errorf("%v: accepting should be retried", err)
+		}
+	}
+}
+
+func TestServerMaxConns(t *testing.T) {
+	InitSRT()
+
+	port := randomPort()
+	srv := &Server{
+		Addr:     "127.0.0.1:" + strconv.Itoa(int(port)),
+		Options:  map[string]string{"transtype": "live"},
+		MaxConns: 1,
+	}
+	release := make(chan struct{})
+	srv.Handle("*", func(conn *Conn, req *Request) {
+		<-release
+	})
+	go srv.ListenAndServe()
+	defer srv.Close()
+
+	var first *Conn
+	var err error
+	for i := 0; i < 10; i++ {
+		if first, err = Dial(srv.Addr, map[string]string{"transtype": "live", "blocking": "0"}); err == nil {
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer first.Close()
+	//Let the server start the handler
+	time.Sleep(100 * time.Millisecond)
+
+	var rejection *RejectionError
+	if _, err := Dial(srv.Addr, map[string]string{"transtype": "live", "blocking": "0"}); !errors.As(err, &rejection) || rejection.Reason != RejectionReasonOverload {
+		t.Errorf("expected an overload rejection, got %v", err)
+	}
+	close(release)
+}
+
+func TestServerAdmit(t *testing.T) {
+	InitSRT()
+
+	port := randomPort()
+	srv := &Server{
+		Addr:    "127.0.0.1:" + strconv.Itoa(int(port)),
+		Options: map[string]string{"transtype": "live"},
+		Admit: func(req *Request) error {
+			if req.ParsedStreamID == nil || req.ParsedStreamID.User != "bob" {
+				return &StreamIDError{StreamID: req.StreamID, Reason: RejectionReasonUnauthorized, Msg: "unknown user"}
+			}
+			return nil
+		},
+	}
+	srv.Handle("*", func(conn *Conn, req *Request) {
+		conn.Write([]byte(req.ParsedStreamID.User))
+	})
+	go srv.ListenAndServe()
+	defer srv.Close()
+
+	var conn *Conn
+	var err error
+	for i := 0; i < 10; i++ {
+		if conn, err = Dial(srv.Addr, map[string]string{"transtype": "live", "blocking": "0", "streamid": "#!::u=bob"}); err == nil {
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	buf := make([]byte, 1500)
+	conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+	if n, err := conn.Read(buf); err != nil || string(buf[:n]) != "bob" {
+		t.Errorf("read %q, %v", buf[:n], err)
+	}
+
+	var rejection *RejectionError
+	if _, err := Dial(srv.Addr, map[string]string{"transtype": "live", "blocking": "0", "streamid": "#!::u=eve"}); !errors.As(err, &rejection) || rejection.Reason != RejectionReasonUnauthorized {
+		t.Errorf("expected an unauthorized rejection, got %v", err)
+	}
+}
@@ -0,0 +1,156 @@
+package srtgo
+
+// #cgo LDFLAGS: -lsrt
+// #include <srt/srt.h>
+import "C"
+
+import (
+	"fmt"
+	"net"
+	"runtime"
+	"strconv"
+	"time"
+)
+
+// SockState - State of an SRT socket (SRT_SOCKSTATUS)
+type SockState int
+
+// Socket states
+const (
+	SockStateInit       = SockState(C.SRTS_INIT)       // created
+	SockStateOpened     = SockState(C.SRTS_OPENED)     // bound
+	SockStateListening  = SockState(C.SRTS_LISTENING)  // listening for callers
+	SockStateConnecting = SockState(C.SRTS_CONNECTING) // connection in progress
+	SockStateConnected  = SockState(C.SRTS_CONNECTED)  // connected, ready to transmit
+	SockStateBroken     = SockState(C.SRTS_BROKEN)     // connection lost
+	SockStateClosing    = SockState(C.SRTS_CLOSING)    // closing in progress
+	SockStateClosed     = SockState(C.SRTS_CLOSED)     // closed
+	SockStateNonExist   = SockState(C.SRTS_NONEXIST)   // unknown socket, or already released
+)
+
+func (s SockState) String() string {
+	switch s {
+	case SockStateInit:
+		return "init"
+	case SockStateOpened:
+		return "opened"
+	case SockStateListening:
+		return "listening"
+	case SockStateConnecting:
+		return "connecting"
+	case SockStateConnected:
+		return "connected"
+	case SockStateBroken:
+		return "broken"
+	case SockStateClosing:
+		return "closing"
+	case SockStateClosed:
+		return "closed"
+	case SockStateNonExist:
+		return "nonexist"
+	}
+	return "SockState(" + strconv.Itoa(int(s)) + ")"
+}
+
+// State - Return the current state of the socket (srt_getsockstate)
+func (s *SrtSocket) State() SockState {
+	return SockState(C.srt_getsockstate(s.socket))
+}
+
+// PeerVersion - Return the SRT version of the connected peer (SRTO_PEERVERSION),
+// zero before the connection is established
+func (s *SrtSocket) PeerVersion() (SrtVersion, error) {
+	v, err := s.GetSockOptInt(SRTO_PEERVERSION)
+	if err != nil {
+		return 0, err
+	}
+	return SrtVersion(v), nil
+}
+
+// ConnectionTime - Return the time the connection was established, in
+// microseconds on the SRT clock (srt_connection_time), see SrtTimeNow
+func (s *SrtSocket) ConnectionTime() (int64, error) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	t := C.srt_connection_time(s.socket)
+	if t == C.int64_t(SRT_ERROR) {
+		return 0, fmt.Errorf("Error in srt_connection_time: %w", srtGetAndClearError())
+	}
+	return int64(t), nil
+}
+
+// ConnectionAge - Return the time elapsed since the connection was established
+func (s *SrtSocket) ConnectionAge() (time.Duration, error) {
+	t, err := s.ConnectionTime()
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(SrtTimeNow()-t) * time.Microsecond, nil
+}
+
+// SocketStatus - Snapshot of the state of a socket, see Status
+type SocketStatus struct {
+	State       SockState
+	LocalAddr   *net.UDPAddr // nil when the socket is not bound
+	RemoteAddr  *net.UDPAddr // nil when the socket is not connected
+	PeerVersion SrtVersion   // zero when the socket is not connected
+
+	// Key material states of the connection, see KMState, SndKMState and
+	// RcvKMState
+	KMState    KMState
+	SndKMState KMState
+	RcvKMState KMState
+	// Length in bytes of the encryption key, 0 when not encrypted
+	PBKeyLen int
+	// Number of packets received since the connection that could not be
+	// decrypted (SrtStats.PktRcvUndecryptTotal)
+	PktRcvUndecryptTotal int
+}
+
+// Status - Return a snapshot of the state of the socket, including its
+// encryption status
+func (s *SrtSocket) Status() (*SocketStatus, error) {
+	st := &SocketStatus{State: s.State()}
+	st.LocalAddr, _ = s.LocalAddr()
+	st.RemoteAddr, _ = s.RemoteAddr()
+
+	var err error
+	if st.PeerVersion, err = s.PeerVersion(); err != nil {
+		return nil, err
+	}
+	if st.KMState, err = s.KMState(); err != nil {
+		return nil, err
+	}
+	if st.SndKMState, err = s.SndKMState(); err != nil {
+		return nil, err
+	}
+	if st.RcvKMState, err = s.RcvKMState(); err != nil {
+		return nil, err
+	}
+	if st.PBKeyLen, err = s.PBKeyLen(); err != nil {
+		return nil, err
+	}
+	//Only connected sockets have statistics
+	if stats, err := s.PeekStats(); err == nil {
+		st.PktRcvUndecryptTotal = stats.PktRcvUndecryptTotal
+	}
+	return st, nil
+}
+
+// Encrypted - Return whether the connection is encrypted with matching keys
+func (st *SocketStatus) Encrypted() bool {
+	return st.KMState == KMStateSecured
+}
+
+// WrongPassphrase - Return whether the passphrases of the peers do not match:
+// either direction reports KMStateBadSecret or KMStateNoSecret, or packets
+// that could not be decrypted were received since the previous snapshot prev,
+// which may be nil.
+func (st *SocketStatus) WrongPassphrase(prev *SocketStatus) bool {
+	for _, k := range []KMState{st.KMState, st.SndKMState, st.RcvKMState} {
+		if k == KMStateBadSecret || k == KMStateNoSecret {
+			return true
+		}
+	}
+	return prev != nil && st.PktRcvUndecryptTotal > prev.PktRcvUndecryptTotal
+}
@@ -0,0 +1,56 @@
+package srtgo
+
+import (
+	"testing"
+)
+
+func TestSockStateString(t *testing.T) {
+	if s := SockStateConnected.String(); s != "connected" {
+		t.Errorf("SockStateConnected is %s", s)
+	}
+	if s := SockState(42).String(); s != "SockState(42)" {
+		t.Errorf("unknown state is %s", s)
+	}
+}
+
+func TestSocketIntrospection(t *testing.T) {
+	ln := NewSrtSocket("127.0.0.1", randomPort(), map[string]string{"blocking": "0", "mode": "listener"})
+	if ln == nil {
+		t.Fatal("failed to create listener socket")
+	}
+	defer ln.Close()
+	if state := ln.State(); state != SockStateInit {
+		t.Errorf("new socket is %s", state)
+	}
+	if v, err := ln.PeerVersion(); err != nil || v != 0 {
+		t.Errorf("peer version before connection: %s, %v", v, err)
+	}
+	if err := ln.Listen(1); err != nil {
+		t.Fatal(err)
+	}
+	if state := ln.State(); state != SockStateListening {
+		t.Errorf("listening socket is %s", state)
+	}
+
+	caller, remote := socketPair(t, map[string]string{"blocking": "0"})
+	if state := caller.State(); state != SockStateConnected {
+		t.Errorf("connected socket is %s", state)
+	}
+	if v, err := remote.PeerVersion(); err != nil || v != LibVersion() {
+		t.Errorf("peer version %s, %v, want %s", v, err, LibVersion())
+	}
+	if age, err := remote.ConnectionAge(); err != nil || age < 0 {
+		t.Errorf("connection age %v, %v", age, err)
+	}
+	local, err := caller.LocalAddr()
+	if err != nil {
+		t.Fatal(err)
+	}
+	peer, err := remote.RemoteAddr()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if local.Port != peer.Port {
+		t.Errorf("caller bound to %s, seen from %s", local, peer)
+	}
+}
@@ -13,12 +13,14 @@ static const int get_srt_error_reject_userdefined() { return SRT_REJC_USERDEFINE
 import "C"
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net"
 	"runtime"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 	"unsafe"
@@ -40,17 +42,24 @@ const (
 	bindingPost = 1
 )
 
-// SrtSocket - SRT socket
+// SrtSocket - SRT socket. Its methods may be called concurrently, except
+// ReadMsg, and Close may be called at any time to interrupt the others.
 type SrtSocket struct {
-	socket      C.int
-	blocking    bool
-	pd          *pollDesc
-	host        string
-	port        uint16
-	options     map[string]string
-	mode        int
-	pktSize     int
+	//Accessed atomically, first for 64-bit alignment on 32-bit platforms
 	pollTimeout int64
+	//The fields below are set once, before the socket is returned
+	socket   C.int
+	blocking bool
+	pd       *pollDesc
+	host     string
+	port     uint16
+	config   Config
+	options  map[string]string
+	mode     int
+	pktSize  int
+	//Set to 1 by Close, accessed atomically
+	closed    int32
+	lastMsgNo int32
 }
 
 var (
@@ -89,34 +98,87 @@ func CleanupSRT() {
 }
 
 // NewSrtSocket - Create a new SRT Socket
+//
+// The options map is converted with ConfigFromOptions. Options that cannot be
+// converted are ignored, as they always have been, except an unknown "mode",
+// which gives a socket in ModeFailure, and a "linger" that is not a number,
+// for which nil is returned like when the socket cannot be configured. The
+// errors are only reported to the log handler, if one is set (see
+// SrtSetLogHandler); use NewSrtSocketE to get them instead.
 func NewSrtSocket(host string, port uint16, options map[string]string) *SrtSocket {
-	s := new(SrtSocket)
-
-	s.socket = C.srt_create_socket()
-	if s.socket == SRT_INVALID_SOCK {
+	config, err := ConfigFromOptions(options)
+	modeFailure := false
+	if err != nil {
+		logError(err)
+		cerr, _ := err.(ConfigError)
+		for _, oe := range cerr {
+			switch oe.Option {
+			case "linger":
+				return nil
+			case "mode":
+				modeFailure = true
+				//Configured like a caller, which sets no mode specific option
+				config.Mode = ModeCaller
+			}
+		}
+	}
+	s, err := newSrtSocket(host, port, config)
+	if err != nil {
+		logError(err)
 		return nil
 	}
+	if modeFailure {
+		s.mode = ModeFailure
+	}
+	return s
+}
+
+// NewSrtSocketE - Create a new SRT Socket like NewSrtSocket, returning an
+// error instead of ignoring invalid options: a ConfigError for options that
+// cannot be converted or are invalid, an UnsupportedOptionError (wrapped) for
+// options the linked libsrt does not support.
+func NewSrtSocketE(host string, port uint16, options map[string]string) (*SrtSocket, error) {
+	config, err := ConfigFromOptions(options)
+	if err != nil {
+		return nil, err
+	}
+	return NewSrtSocketWithConfig(host, port, config)
+}
+
+// NewSrtSocketWithConfig - Create a new SRT Socket from a typed configuration.
+// The configuration is validated first, and every invalid option is reported
+// in the returned ConfigError.
+func NewSrtSocketWithConfig(host string, port uint16, config Config) (*SrtSocket, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return newSrtSocket(host, port, config)
+}
 
+func newSrtSocket(host string, port uint16, config Config) (*SrtSocket, error) {
+	socket := C.srt_create_socket()
+	if socket == SRT_INVALID_SOCK {
+		return nil, fmt.Errorf("could not create SRT socket")
+	}
+	return initSrtSocket(socket, host, port, config)
+}
+
+// initSrtSocket - Wrap a freshly created SRT socket or group and configure it
+func initSrtSocket(socket C.SRTSOCKET, host string, port uint16, config Config) (*SrtSocket, error) {
+	s := new(SrtSocket)
+	s.socket = socket
 	s.host = host
 	s.port = port
-	s.options = options
+	s.config = config
+	s.options = config.options()
 	s.pollTimeout = -1
 
-	val, exists := options["pktsize"]
-	if exists {
-		pktSize, err := strconv.Atoi(val)
-		if err != nil {
-			s.pktSize = pktSize
-		}
-	}
+	s.pktSize = config.PacketSize
 	if s.pktSize <= 0 {
 		s.pktSize = defaultPacketSize
 	}
 
-	val, exists = options["blocking"]
-	if exists && val != "0" {
-		s.blocking = true
-	}
+	s.blocking = config.Blocking
 
 	if !s.blocking {
 		s.pd = pollDescInit(s.socket)
@@ -136,10 +198,10 @@ func NewSrtSocket(host string, port uint16, options map[string]string) *SrtSocke
 	var err error
 	s.mode, err = s.preconfiguration()
 	if err != nil {
-		return nil
+		return nil, err
 	}
 
-	return s
+	return s, nil
 }
 
 func newFromSocket(acceptSocket *SrtSocket, socket C.SRTSOCKET) (*SrtSocket, error) {
@@ -147,7 +209,7 @@ func newFromSocket(acceptSocket *SrtSocket, socket C.SRTSOCKET) (*SrtSocket, err
 	s.socket = socket
 	s.pktSize = acceptSocket.pktSize
 	s.blocking = acceptSocket.blocking
-	s.pollTimeout = acceptSocket.pollTimeout
+	s.pollTimeout = atomic.LoadInt64(&acceptSocket.pollTimeout)
 
 	err := acceptSocket.postconfiguration(s)
 	if err != nil {
@@ -172,7 +234,7 @@ func newFromSocket(acceptSocket *SrtSocket, socket C.SRTSOCKET) (*SrtSocket, err
 	return s, nil
 }
 
-func (s SrtSocket) GetSocket() C.int {
+func (s *SrtSocket) GetSocket() C.int {
 	return s.socket
 }
 
@@ -209,8 +271,37 @@ func (s *SrtSocket) Listen(backlog int) error {
 	return nil
 }
 
-// Connect to a remote endpoint
+// Connect to a remote endpoint. When the "adapter" or "localport" options are
+// set, the socket is first bound to that local address, otherwise libsrt picks
+// the source address. In rendezvous mode the socket is always bound (the local
+// port defaults to the remote port), and the remote endpoint has to connect
+// back to it at the same time.
+//
+// In non-blocking mode, the wait for the connection is bounded by the write
+// deadline and by the poll timeout when one is set (see SetPollTimeout), whose
+// expiry is reported as a *SrtEpollTimeout. Use ConnectContext for finer control.
+//
+// A connection rejected by the peer, or timing out, is reported as a
+// *RejectionError giving the reason.
 func (s *SrtSocket) Connect() error {
+	if s.isClosed() {
+		return &SrtSocketClosed{}
+	}
+	if !s.blocking && s.PollTimeout() > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), s.PollTimeout())
+		defer cancel()
+		err := s.ConnectContext(ctx)
+		//The poll timeout has always been reported as an epoll timeout
+		if err == context.DeadlineExceeded {
+			return &SrtEpollTimeout{}
+		}
+		return err
+	}
+	return s.connect(nil)
+}
+
+// connect - Connect the socket, giving up when done is closed
+func (s *SrtSocket) connect(done <-chan struct{}) error {
 	runtime.LockOSThread()
 	defer runtime.UnlockOSThread()
 	sa, salen, err := CreateAddrInet(s.host, s.port)
@@ -218,15 +309,41 @@ func (s *SrtSocket) Connect() error {
 		return err
 	}
 
-	res := C.srt_connect(s.socket, sa, C.int(salen))
+	var res C.int
+	if s.mode == ModeRendezvouz {
+		la, lalen, err := s.connectLocalAddr(sa, s.port)
+		if err != nil {
+			return err
+		}
+		res = C.srt_rendezvous(s.socket, la, C.int(lalen), sa, C.int(salen))
+	} else {
+		if s.config.Adapter != "" || s.config.LocalPort != 0 {
+			la, lalen, err := s.connectLocalAddr(sa, 0)
+			if err != nil {
+				return err
+			}
+			if C.srt_bind(s.socket, la, C.int(lalen)) == SRT_ERROR {
+				C.srt_close(s.socket)
+				return fmt.Errorf("Error in srt_bind: %w", srtGetAndClearError())
+			}
+		}
+		res = C.srt_connect(s.socket, sa, C.int(salen))
+	}
 	if res == SRT_ERROR {
+		err := s.rejectionError(srtGetAndClearError())
 		C.srt_close(s.socket)
-		return srtGetAndClearError()
+		return err
 	}
 
 	if !s.blocking {
-		if err := s.pd.wait(ModeWrite); err != nil {
-			return err
+		if err := s.pd.waitDone(ModeWrite, done); err != nil {
+			if s.isClosed() {
+				return &SrtSocketClosed{}
+			}
+			if err == errWaitInterrupted {
+				return err
+			}
+			return s.rejectionError(err)
 		}
 	}
 
@@ -238,12 +355,40 @@ func (s *SrtSocket) Connect() error {
 	return nil
 }
 
+// connectLocalAddr - Return the local address to bind to before connecting to
+// remote: the "adapter" option, or the any address of the family of remote,
+// and the "localport" option, or defaultPort
+func (s *SrtSocket) connectLocalAddr(remote *C.struct_sockaddr, defaultPort uint16) (*C.struct_sockaddr, int, error) {
+	host := s.config.Adapter
+	if host == "" {
+		host = "0.0.0.0"
+		if remote.sa_family == afINET6 {
+			host = "::"
+		}
+	}
+	port := s.config.LocalPort
+	if port == 0 {
+		port = defaultPort
+	}
+	return CreateAddrInet(host, port)
+}
+
 // Stats - Retrieve stats from the SRT socket
-func (s SrtSocket) Stats() (*SrtStats, error) {
+func (s *SrtSocket) Stats() (*SrtStats, error) {
+	return s.stats(1)
+}
+
+// PeekStats - Retrieve stats from the SRT socket without clearing the local
+// (interval) measurements, for monitoring alongside an application calling Stats
+func (s *SrtSocket) PeekStats() (*SrtStats, error) {
+	return s.stats(0)
+}
+
+func (s *SrtSocket) stats(clear C.int) (*SrtStats, error) {
 	runtime.LockOSThread()
 	defer runtime.UnlockOSThread()
 	var stats C.SRT_TRACEBSTATS = C.SRT_TRACEBSTATS{}
-	var b C.int = 1
+	var b C.int = clear
 	if C.srt_bstats(s.socket, &stats, b) == SRT_ERROR {
 		return nil, fmt.Errorf("Error getting stats, %w", srtGetAndClearError())
 	}
@@ -252,25 +397,55 @@ func (s SrtSocket) Stats() (*SrtStats, error) {
 }
 
 // Mode - Return working mode of the SRT socket
-func (s SrtSocket) Mode() int {
+func (s *SrtSocket) Mode() int {
 	return s.mode
 }
 
+// ID - Return the libsrt identifier of the SRT socket, unique among the open
+// sockets (as in GroupMember.ID)
+func (s *SrtSocket) ID() int {
+	return int(s.socket)
+}
+
 // PacketSize - Return packet size of the SRT socket
-func (s SrtSocket) PacketSize() int {
+func (s *SrtSocket) PacketSize() int {
 	return s.pktSize
 }
 
+// LocalAddr - Return the local address the socket is bound to (srt_getsockname)
+func (s *SrtSocket) LocalAddr() (*net.UDPAddr, error) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	var addr syscall.RawSockaddrAny
+	sclen := C.int(sizeofSockaddrAny)
+	if C.srt_getsockname(s.socket, (*C.struct_sockaddr)(unsafe.Pointer(&addr)), &sclen) == SRT_ERROR {
+		return nil, fmt.Errorf("Error in srt_getsockname: %w", srtGetAndClearError())
+	}
+	return udpAddrFromSockaddr(&addr)
+}
+
+// RemoteAddr - Return the address of the connected peer, using srt_getpeername
+func (s *SrtSocket) RemoteAddr() (*net.UDPAddr, error) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	var addr syscall.RawSockaddrAny
+	sclen := C.int(sizeofSockaddrAny)
+	if C.srt_getpeername(s.socket, (*C.struct_sockaddr)(unsafe.Pointer(&addr)), &sclen) == SRT_ERROR {
+		return nil, fmt.Errorf("Error in srt_getpeername: %w", srtGetAndClearError())
+	}
+	return udpAddrFromSockaddr(&addr)
+}
+
 // PollTimeout - Return polling max time, for connect/read/write operations.
 // Only applied when socket is in non-blocking mode.
-func (s SrtSocket) PollTimeout() time.Duration {
-	return time.Duration(s.pollTimeout) * time.Millisecond
+func (s *SrtSocket) PollTimeout() time.Duration {
+	return time.Duration(atomic.LoadInt64(&s.pollTimeout)) * time.Millisecond
 }
 
 // SetPollTimeout - Sets polling max time, for connect/read/write operations.
 // Only applied when socket is in non-blocking mode.
 func (s *SrtSocket) SetPollTimeout(pollTimeout time.Duration) {
-	s.pollTimeout = pollTimeout.Milliseconds()
+	atomic.StoreInt64(&s.pollTimeout, pollTimeout.Milliseconds())
 }
 
 func (s *SrtSocket) SetDeadline(deadline time.Time) {
@@ -285,28 +460,63 @@ func (s *SrtSocket) SetWriteDeadline(deadline time.Time) {
 	s.pd.setDeadline(deadline, ModeWrite)
 }
 
-// Close the SRT socket
-func (s *SrtSocket) Close() {
+// Close the SRT socket. Close is safe to call concurrently with the other
+// methods: pending reads, writes and accepts are interrupted and return a
+// *SrtSocketClosed error, like any operation started after Close. Only the
+// first call closes the socket, any later call returns a *SrtSocketClosed error.
+func (s *SrtSocket) Close() error {
+	if !atomic.CompareAndSwapInt32(&s.closed, 0, 1) {
+		return &SrtSocketClosed{}
+	}
 
-	C.srt_close(s.socket)
-	socket := s.socket
-	s.socket = SRT_INVALID_SOCK
+	err := s.srtClose()
 	if !s.blocking {
 		s.pd.close()
 	}
 	callbackMutex.Lock()
-	if ptr, exists := listenCallbackMap[socket]; exists {
+	if ptr, exists := listenCallbackMap[s.socket]; exists {
 		gopointer.Unref(ptr)
-		delete(listenCallbackMap, socket)
+		delete(listenCallbackMap, s.socket)
 	}
-	if ptr, exists := connectCallbackMap[socket]; exists {
+	if ptr, exists := connectCallbackMap[s.socket]; exists {
 		gopointer.Unref(ptr)
-		delete(connectCallbackMap, socket)
+		delete(connectCallbackMap, s.socket)
 	}
 	callbackMutex.Unlock()
+	return err
 }
 
-// ListenCallbackFunc specifies a function to be called before a connecting socket is passed to accept
+func (s *SrtSocket) srtClose() error {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	if C.srt_close(s.socket) == SRT_ERROR {
+		err := srtGetAndClearError()
+		//Already closed by libsrt, e.g. by a failed Connect
+		if errors.Is(err, EInvSock) {
+			return nil
+		}
+		return fmt.Errorf("Error in srt_close: %w", err)
+	}
+	return nil
+}
+
+// isClosed - Return whether Close was called
+func (s *SrtSocket) isClosed() bool {
+	return atomic.LoadInt32(&s.closed) != 0
+}
+
+// closedError - Report err as a *SrtSocketClosed error when it was caused by a
+// concurrent Close
+func (s *SrtSocket) closedError(err error) error {
+	if err != nil && s.isClosed() {
+		return &SrtSocketClosed{}
+	}
+	return err
+}
+
+// ListenCallbackFunc specifies a function to be called before a connecting socket is passed to accept.
+// The callback can set the options of this connection, such as its passphrase,
+// on socket with SetConnectionOptions.
 type ListenCallbackFunc func(socket *SrtSocket, version int, addr *net.UDPAddr, streamid string) bool
 
 //export srtListenCBWrapper
@@ -327,7 +537,7 @@ func srtListenCBWrapper(arg unsafe.Pointer, socket C.SRTSOCKET, hsVersion C.int,
 // is handed to accept on a listening socket.
 // The connection can be rejected by returning false from the callback.
 // See examples/echo-receiver for more details.
-func (s SrtSocket) SetListenCallback(cb ListenCallbackFunc) {
+func (s *SrtSocket) SetListenCallback(cb ListenCallbackFunc) {
 	ptr := gopointer.Save(cb)
 	C.srt_listen_callback(s.socket, (*C.srt_listen_callback_fn)(C.srtListenCB), ptr)
 
@@ -339,6 +549,31 @@ func (s SrtSocket) SetListenCallback(cb ListenCallbackFunc) {
 	listenCallbackMap[s.socket] = ptr
 }
 
+// Options that can be set per connection in a ListenCallbackFunc
+var connectionOptions = []string{"passphrase", "pbkeylen", "latency", "rcvlatency", "peerlatency", "maxbw"}
+
+// SetConnectionOptions - Set options of a connection being accepted, on the
+// socket given to a ListenCallbackFunc, before the handshake completes.
+// Only "passphrase", "pbkeylen", "latency", "rcvlatency", "peerlatency" and
+// "maxbw" are accepted, with the same values as in NewSrtSocket. Setting the
+// passphrase lets a single listener use a different one for every caller, a
+// caller with another passphrase is rejected.
+func (s *SrtSocket) SetConnectionOptions(options map[string]string) error {
+	for name := range options {
+		allowed := false
+		for _, o := range connectionOptions {
+			if o == name {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("option %s cannot be set per connection", name)
+		}
+	}
+	return setSocketOptions(s.socket, bindingPre, options)
+}
+
 // ConnectCallbackFunc specifies a function to be called after a socket or connection in a group has failed.
 type ConnectCallbackFunc func(socket *SrtSocket, err error, addr *net.UDPAddr, token int)
 
@@ -355,7 +590,7 @@ func srtConnectCBWrapper(arg unsafe.Pointer, socket C.SRTSOCKET, errcode C.int,
 
 // SetConnectCallback - set a function to be called after a socket or connection in a group has failed
 // Note that the function is not guaranteed to be called if the socket is set to blocking mode.
-func (s SrtSocket) SetConnectCallback(cb ConnectCallbackFunc) {
+func (s *SrtSocket) SetConnectCallback(cb ConnectCallbackFunc) {
 	ptr := gopointer.Save(cb)
 	C.srt_connect_callback(s.socket, (*C.srt_connect_callback_fn)(C.srtConnectCB), ptr)
 
@@ -394,11 +629,11 @@ var (
 	RejectionReasonUnacceptable = RejectionReasonPredefined + 406
 
 	// Start of range for application defined rejection reasons
-	RejectionReasonUserDefined = int(C.get_srt_error_reject_predefined())
+	RejectionReasonUserDefined = int(C.get_srt_error_reject_userdefined())
 )
 
 // SetRejectReason - set custom reason for connection reject
-func (s SrtSocket) SetRejectReason(value int) error {
+func (s *SrtSocket) SetRejectReason(value int) error {
 	res := C.srt_setrejectreason(s.socket, C.int(value))
 	if res == SRT_ERROR {
 		return errors.New(C.GoString(C.srt_getlasterror_str()))
@@ -407,7 +642,7 @@ func (s SrtSocket) SetRejectReason(value int) error {
 }
 
 // GetSockOptByte - return byte value obtained with srt_getsockopt
-func (s SrtSocket) GetSockOptByte(opt int) (byte, error) {
+func (s *SrtSocket) GetSockOptByte(opt int) (byte, error) {
 	var v byte
 	l := 1
 
@@ -416,7 +651,7 @@ func (s SrtSocket) GetSockOptByte(opt int) (byte, error) {
 }
 
 // GetSockOptBool - return bool value obtained with srt_getsockopt
-func (s SrtSocket) GetSockOptBool(opt int) (bool, error) {
+func (s *SrtSocket) GetSockOptBool(opt int) (bool, error) {
 	var v int32
 	l := 4
 
@@ -429,7 +664,7 @@ func (s SrtSocket) GetSockOptBool(opt int) (bool, error) {
 }
 
 // GetSockOptInt - return int value obtained with srt_getsockopt
-func (s SrtSocket) GetSockOptInt(opt int) (int, error) {
+func (s *SrtSocket) GetSockOptInt(opt int) (int, error) {
 	var v int32
 	l := 4
 
@@ -438,7 +673,7 @@ func (s SrtSocket) GetSockOptInt(opt int) (int, error) {
 }
 
 // GetSockOptInt64 - return int64 value obtained with srt_getsockopt
-func (s SrtSocket) GetSockOptInt64(opt int) (int64, error) {
+func (s *SrtSocket) GetSockOptInt64(opt int) (int64, error) {
 	var v int64
 	l := 8
 
@@ -447,8 +682,9 @@ func (s SrtSocket) GetSockOptInt64(opt int) (int64, error) {
 }
 
 // GetSockOptString - return string value obtained with srt_getsockopt
-func (s SrtSocket) GetSockOptString(opt int) (string, error) {
-	buf := make([]byte, 256)
+func (s *SrtSocket) GetSockOptString(opt int) (string, error) {
+	//Large enough for the longest string option, SRTO_STREAMID
+	buf := make([]byte, maxStreamIDLen)
 	l := len(buf)
 
 	err := s.getSockOpt(opt, unsafe.Pointer(&buf[0]), &l)
@@ -459,12 +695,12 @@ func (s SrtSocket) GetSockOptString(opt int) (string, error) {
 }
 
 // SetSockOptByte - set byte value using srt_setsockopt
-func (s SrtSocket) SetSockOptByte(opt int, value byte) error {
+func (s *SrtSocket) SetSockOptByte(opt int, value byte) error {
 	return s.setSockOpt(opt, unsafe.Pointer(&value), 1)
 }
 
 // SetSockOptBool - set bool value using srt_setsockopt
-func (s SrtSocket) SetSockOptBool(opt int, value bool) error {
+func (s *SrtSocket) SetSockOptBool(opt int, value bool) error {
 	val := int(0)
 	if value {
 		val = 1
@@ -473,21 +709,27 @@ func (s SrtSocket) SetSockOptBool(opt int, value bool) error {
 }
 
 // SetSockOptInt - set int value using srt_setsockopt
-func (s SrtSocket) SetSockOptInt(opt int, value int) error {
+func (s *SrtSocket) SetSockOptInt(opt int, value int) error {
 	return s.setSockOpt(opt, unsafe.Pointer(&value), 4)
 }
 
 // SetSockOptInt64 - set int64 value using srt_setsockopt
-func (s SrtSocket) SetSockOptInt64(opt int, value int64) error {
+func (s *SrtSocket) SetSockOptInt64(opt int, value int64) error {
 	return s.setSockOpt(opt, unsafe.Pointer(&value), 8)
 }
 
 // SetSockOptString - set string value using srt_setsockopt
-func (s SrtSocket) SetSockOptString(opt int, value string) error {
+func (s *SrtSocket) SetSockOptString(opt int, value string) error {
 	return s.setSockOpt(opt, unsafe.Pointer(&[]byte(value)[0]), len(value))
 }
 
-func (s SrtSocket) setSockOpt(opt int, data unsafe.Pointer, size int) error {
+func (s *SrtSocket) setSockOpt(opt int, data unsafe.Pointer, size int) error {
+	if s.isClosed() {
+		return &SrtSocketClosed{}
+	}
+	if err := checkOptionSupported(opt); err != nil {
+		return err
+	}
 	runtime.LockOSThread()
 	defer runtime.UnlockOSThread()
 	res := C.srt_setsockopt(s.socket, 0, C.SRT_SOCKOPT(opt), data, C.int(size))
@@ -497,7 +739,13 @@ func (s SrtSocket) setSockOpt(opt int, data unsafe.Pointer, size int) error {
 	return nil
 }
 
-func (s SrtSocket) getSockOpt(opt int, data unsafe.Pointer, size *int) error {
+func (s *SrtSocket) getSockOpt(opt int, data unsafe.Pointer, size *int) error {
+	if s.isClosed() {
+		return &SrtSocketClosed{}
+	}
+	if err := checkOptionSupported(opt); err != nil {
+		return err
+	}
 	runtime.LockOSThread()
 	defer runtime.UnlockOSThread()
 	res := C.srt_getsockopt(s.socket, 0, C.SRT_SOCKOPT(opt), data, (*C.int)(unsafe.Pointer(size)))
@@ -508,7 +756,7 @@ func (s SrtSocket) getSockOpt(opt int, data unsafe.Pointer, size *int) error {
 	return nil
 }
 
-func (s SrtSocket) preconfiguration() (int, error) {
+func (s *SrtSocket) preconfiguration() (int, error) {
 	runtime.LockOSThread()
 	defer runtime.UnlockOSThread()
 	var blocking C.int
@@ -532,6 +780,8 @@ func (s SrtSocket) preconfiguration() (int, error) {
 		mode = ModeCaller
 	} else if modeVal == "server" || modeVal == "listener" {
 		mode = ModeListener
+	} else if modeVal == "rendezvous" {
+		mode = ModeRendezvouz
 	} else if modeVal == "default" {
 		if s.host == "" {
 			mode = ModeListener
@@ -547,6 +797,14 @@ func (s SrtSocket) preconfiguration() (int, error) {
 		mode = ModeFailure
 	}
 
+	if mode == ModeRendezvouz {
+		rendezvous := C.int(1)
+		result = C.srt_setsockopt(s.socket, 0, C.SRTO_RENDEZVOUS, unsafe.Pointer(&rendezvous), C.int(unsafe.Sizeof(rendezvous)))
+		if result == -1 {
+			return ModeFailure, fmt.Errorf("could not set SRTO_RENDEZVOUS flag: %w", srtGetAndClearError())
+		}
+	}
+
 	if linger, ok := s.options["linger"]; ok {
 		li, err := strconv.Atoi(linger)
 		if err == nil {
@@ -566,7 +824,7 @@ func (s SrtSocket) preconfiguration() (int, error) {
 	return mode, nil
 }
 
-func (s SrtSocket) postconfiguration(sck *SrtSocket) error {
+func (s *SrtSocket) postconfiguration(sck *SrtSocket) error {
 	runtime.LockOSThread()
 	defer runtime.UnlockOSThread()
 	var blocking C.int
@@ -16,6 +16,52 @@ func randomPort() uint16 {
 	return uint16(rand.Intn(32768-1024) + 1024)
 }
 
+// socketPair - Return a connected caller and the socket accepted for it, both
+// created with options
+func socketPair(t testing.TB, options map[string]string) (*SrtSocket, *SrtSocket) {
+	t.Helper()
+	return socketPairOptions(t, options, options)
+}
+
+// socketPairOptions - Like socketPair, with different options for the listener
+// and the caller. "mode" is set on copies of the options. Both sockets are
+// closed when the test ends.
+func socketPairOptions(t testing.TB, listenerOptions, callerOptions map[string]string) (*SrtSocket, *SrtSocket) {
+	t.Helper()
+	port := randomPort()
+	lnOptions := copyOptions(listenerOptions)
+	lnOptions["mode"] = "listener"
+	ln := NewSrtSocket("127.0.0.1", port, lnOptions)
+	if ln == nil {
+		t.Fatal("failed to create listener socket")
+	}
+	t.Cleanup(func() { ln.Close() })
+	if err := ln.Listen(1); err != nil {
+		t.Fatal(err)
+	}
+
+	cOptions := copyOptions(callerOptions)
+	cOptions["mode"] = "caller"
+	caller := NewSrtSocket("127.0.0.1", port, cOptions)
+	if caller == nil {
+		t.Fatal("failed to create caller socket")
+	}
+	t.Cleanup(func() { caller.Close() })
+	connected := make(chan error, 1)
+	go func() {
+		connected <- caller.Connect()
+	}()
+	remote, _, err := ln.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { remote.Close() })
+	if err := <-connected; err != nil {
+		t.Fatal(err)
+	}
+	return caller, remote
+}
+
 func TestNewSocket(t *testing.T) {
 	options := make(map[string]string)
 	a := NewSrtSocket("localhost", 8090, options)
@@ -63,6 +109,26 @@ func TestNewSocketLinger(t *testing.T) {
 	}
 }
 
+func TestNewSocketInvalidLinger(t *testing.T) {
+	a := NewSrtSocket("localhost", 8090, map[string]string{"linger": "1s"})
+	if a != nil {
+		a.Close()
+		t.Error("a socket with an invalid linger should not be created")
+	}
+}
+
+func TestNewSocketInvalidMode(t *testing.T) {
+	a := NewSrtSocket("localhost", 8090, map[string]string{"mode": "bogus"})
+	if a == nil {
+		t.Fatal("Could not create a srt socket")
+	}
+	defer a.Close()
+
+	if a.Mode() != ModeFailure {
+		t.Errorf("mode is %d, expected ModeFailure", a.Mode())
+	}
+}
+
 func TestNewSocketWithTransType(t *testing.T) {
 	options := make(map[string]string)
 	options["transtype"] = "3"
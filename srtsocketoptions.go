@@ -28,6 +28,9 @@ const (
 	SRTO_MAXBW              = C.SRTO_MAXBW
 	SRTO_PBKEYLEN           = C.SRTO_PBKEYLEN
 	SRTO_PASSPHRASE         = C.SRTO_PASSPHRASE
+	SRTO_KMSTATE            = C.SRTO_KMSTATE
+	SRTO_SNDKMSTATE         = C.SRTO_SNDKMSTATE
+	SRTO_RCVKMSTATE         = C.SRTO_RCVKMSTATE
 	SRTO_MSS                = C.SRTO_MSS
 	SRTO_FC                 = C.SRTO_FC
 	SRTO_SNDBUF             = C.SRTO_SNDBUF
@@ -41,6 +44,8 @@ const (
 	SRTO_TLPKTDROP          = C.SRTO_TLPKTDROP
 	SRTO_SNDDROPDELAY       = C.SRTO_SNDDROPDELAY
 	SRTO_NAKREPORT          = C.SRTO_NAKREPORT
+	SRTO_VERSION            = C.SRTO_VERSION
+	SRTO_PEERVERSION        = C.SRTO_PEERVERSION
 	SRTO_CONNTIMEO          = C.SRTO_CONNTIMEO
 	SRTO_LOSSMAXTTL         = C.SRTO_LOSSMAXTTL
 	SRTO_RCVLATENCY         = C.SRTO_RCVLATENCY
@@ -56,6 +61,26 @@ const (
 	SRTO_PEERIDLETIMEO      = C.SRTO_PEERIDLETIMEO
 	SRTO_PACKETFILTER       = C.SRTO_PACKETFILTER
 	SRTO_STATE              = C.SRTO_STATE
+	SRTO_EVENT              = C.SRTO_EVENT
+	SRTO_UDP_SNDBUF         = C.SRTO_UDP_SNDBUF
+	SRTO_UDP_RCVBUF         = C.SRTO_UDP_RCVBUF
+	SRTO_RENDEZVOUS         = C.SRTO_RENDEZVOUS
+)
+
+// Options added after SRT 1.4.1. Their values shadow the SRT_SOCKOPT enum
+// instead of using the C constants, so the package still builds against
+// headers that do not declare all of them; whether the linked libsrt supports
+// an option is checked at runtime, see optionMinVersion.
+const (
+	SRTO_DRIFTTRACER         = 37
+	SRTO_MININPUTBW          = 38
+	SRTO_IPV6ONLY            = 54
+	SRTO_BINDTODEVICE        = 56
+	SRTO_GROUPCONNECT        = 57
+	SRTO_GROUPMINSTABLETIMEO = 58
+	SRTO_GROUPTYPE           = 59
+	SRTO_RETRANSMITALGO      = 61
+	SRTO_CRYPTOMODE          = 62
 )
 
 type socketOption struct {
@@ -99,6 +124,60 @@ var SocketOptions = []socketOption{
 	{"enforcedencryption", 0, SRTO_ENFORCEDENCRYPTION, bindingPre, tBoolean},
 	{"peeridletimeo", 0, SRTO_PEERIDLETIMEO, bindingPre, tInteger32},
 	{"packetfilter", 0, SRTO_PACKETFILTER, bindingPre, tString},
+	{"udp_sndbuf", 0, SRTO_UDP_SNDBUF, bindingPre, tInteger32},
+	{"udp_rcvbuf", 0, SRTO_UDP_RCVBUF, bindingPre, tInteger32},
+	{"ipv6only", 0, SRTO_IPV6ONLY, bindingPre, tInteger32},
+	{"bindtodevice", 0, SRTO_BINDTODEVICE, bindingPre, tString},
+	{"retransmitalgo", 0, SRTO_RETRANSMITALGO, bindingPre, tInteger32},
+	{"drifttracer", 0, SRTO_DRIFTTRACER, bindingPost, tBoolean},
+	{"mininputbw", 0, SRTO_MININPUTBW, bindingPost, tInteger64},
+	{"groupconnect", 0, SRTO_GROUPCONNECT, bindingPre, tInteger32},
+	{"groupminstabletimeo", 0, SRTO_GROUPMINSTABLETIMEO, bindingPre, tInteger32},
+	{"cryptomode", 0, SRTO_CRYPTOMODE, bindingPre, tInteger32},
+}
+
+// First libsrt version supporting an option, for the options that are newer
+// than the oldest libsrt srtgo builds against
+var optionMinVersion = map[int]SrtVersion{
+	SRTO_DRIFTTRACER:         MakeSrtVersion(1, 4, 2),
+	SRTO_MININPUTBW:          MakeSrtVersion(1, 4, 3),
+	SRTO_BINDTODEVICE:        MakeSrtVersion(1, 4, 2),
+	SRTO_GROUPCONNECT:        MakeSrtVersion(1, 5, 0),
+	SRTO_GROUPMINSTABLETIMEO: MakeSrtVersion(1, 5, 0),
+	SRTO_GROUPTYPE:           MakeSrtVersion(1, 5, 0),
+	SRTO_RETRANSMITALGO:      MakeSrtVersion(1, 4, 2),
+	SRTO_CRYPTOMODE:          MakeSrtVersion(1, 5, 2),
+}
+
+// UnsupportedOptionError - The option is not supported by the linked libsrt
+type UnsupportedOptionError struct {
+	Option   string
+	Required SrtVersion
+	Linked   SrtVersion
+}
+
+func (e *UnsupportedOptionError) Error() string {
+	return fmt.Sprintf("option %s requires libsrt %s or newer, linked libsrt is %s", e.Option, e.Required, e.Linked)
+}
+
+// checkOptionSupported - Return an UnsupportedOptionError when opt is newer than the linked libsrt
+func checkOptionSupported(opt int) error {
+	required, ok := optionMinVersion[opt]
+	if !ok {
+		return nil
+	}
+	linked := LibVersion()
+	if linked >= required {
+		return nil
+	}
+	name := fmt.Sprintf("%d", opt)
+	for _, so := range SocketOptions {
+		if so.option == opt {
+			name = so.name
+			break
+		}
+	}
+	return &UnsupportedOptionError{Option: name, Required: required, Linked: linked}
 }
 
 func setSocketLingerOption(s C.int, li int32) error {
@@ -134,22 +213,27 @@ func setSocketOptions(s C.int, binding int, options map[string]string) error {
 	for _, so := range SocketOptions {
 		if val, ok := options[so.name]; ok {
 			if so.binding == binding {
+				if err := checkOptionSupported(so.option); err != nil {
+					return err
+				}
 				if so.dataType == tInteger32 {
 					v, err := strconv.Atoi(val)
+					if err != nil {
+						return fmt.Errorf("invalid value %s for option %s: %w", val, so.name, err)
+					}
 					v32 := int32(v)
-					if err == nil {
-						result := C.srt_setsockflag(s, C.SRT_SOCKOPT(so.option), unsafe.Pointer(&v32), C.int32_t(unsafe.Sizeof(v32)))
-						if result == -1 {
-							return fmt.Errorf("warning - error setting option %s to %s, %w", so.name, val, srtGetAndClearError())
-						}
+					result := C.srt_setsockflag(s, C.SRT_SOCKOPT(so.option), unsafe.Pointer(&v32), C.int32_t(unsafe.Sizeof(v32)))
+					if result == -1 {
+						return fmt.Errorf("warning - error setting option %s to %s, %w", so.name, val, srtGetAndClearError())
 					}
 				} else if so.dataType == tInteger64 {
 					v, err := strconv.ParseInt(val, 10, 64)
-					if err == nil {
-						result := C.srt_setsockflag(s, C.SRT_SOCKOPT(so.option), unsafe.Pointer(&v), C.int32_t(unsafe.Sizeof(v)))
-						if result == -1 {
-							return fmt.Errorf("warning - error setting option %s to %s, %w", so.name, val, srtGetAndClearError())
-						}
+					if err != nil {
+						return fmt.Errorf("invalid value %s for option %s: %w", val, so.name, err)
+					}
+					result := C.srt_setsockflag(s, C.SRT_SOCKOPT(so.option), unsafe.Pointer(&v), C.int32_t(unsafe.Sizeof(v)))
+					if result == -1 {
+						return fmt.Errorf("warning - error setting option %s to %s, %w", so.name, val, srtGetAndClearError())
 					}
 				} else if so.dataType == tString {
 					sval := C.CString(val)
@@ -167,6 +251,8 @@ func setSocketOptions(s C.int, binding int, options map[string]string) error {
 					} else if val == "0" {
 						v := C.char(0)
 						result = C.srt_setsockflag(s, C.SRT_SOCKOPT(so.option), unsafe.Pointer(&v), C.int32_t(unsafe.Sizeof(v)))
+					} else {
+						return fmt.Errorf("invalid value %s for option %s: expected 0 or 1", val, so.name)
 					}
 					if result == -1 {
 						return fmt.Errorf("warning - error setting option %s to %s, %w", so.name, val, srtGetAndClearError())
@@ -179,6 +265,8 @@ func setSocketOptions(s C.int, binding int, options map[string]string) error {
 					} else if val == "file" {
 						var v int32 = C.SRTT_FILE
 						result = C.srt_setsockflag(s, C.SRT_SOCKOPT(so.option), unsafe.Pointer(&v), C.int32_t(unsafe.Sizeof(v)))
+					} else {
+						return fmt.Errorf("invalid value %s for option %s: expected live or file", val, so.name)
 					}
 					if result == -1 {
 						return fmt.Errorf("warning - error setting option %s to %s: %w", so.name, val, srtGetAndClearError())
@@ -0,0 +1,157 @@
+package srtgo
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// streamIDPrefix starts every StreamID in the SRT access control syntax
+const streamIDPrefix = "#!::"
+
+// StreamIDMode - Mode of a connection, the "m" key of a StreamID
+type StreamIDMode string
+
+// StreamID modes
+const (
+	StreamIDModeRequest       = StreamIDMode("request")       // the caller wants to receive data (default)
+	StreamIDModePublish       = StreamIDMode("publish")       // the caller wants to send data
+	StreamIDModeBidirectional = StreamIDMode("bidirectional") // the caller wants to send and receive data
+)
+
+// StreamIDType - Purpose of a connection, the "t" key of a StreamID
+type StreamIDType string
+
+// StreamID types
+const (
+	StreamIDTypeStream = StreamIDType("stream") // live streaming (default)
+	StreamIDTypeFile   = StreamIDType("file")   // file transmission
+	StreamIDTypeAuth   = StreamIDType("auth")   // authentication only, no data
+)
+
+// StreamID - StreamID in the SRT access control syntax:
+// #!::r=resource,m=publish,u=user,h=host,s=session,t=type,key=value...
+//
+// Empty fields are left out of String. Values cannot contain commas, the
+// syntax has no escaping.
+type StreamID struct {
+	Resource string       // r: name of the resource
+	Mode     StreamIDMode // m: mode of the connection, request when empty
+	User     string       // u: user name
+	Host     string       // h: host name of the resource, like in the HTTP Host header
+	Session  string       // s: session ID
+	Type     StreamIDType // t: purpose of the connection, stream when empty
+	// Keys other than the standard ones, in no particular order
+	Custom map[string]string
+}
+
+// StreamIDError - Error parsing or admitting a StreamID, with the rejection
+// reason to give to the caller (see StreamIDRejectionReason). ParseStreamID
+// reports RejectionReasonBadRequest or RejectionReasonBadMode, the listeners
+// checking the parsed StreamID may use any other reason, such as
+// RejectionReasonForbidden for a resource they refuse.
+type StreamIDError struct {
+	StreamID string
+	Reason   int // RejectionReason* value
+	Msg      string
+}
+
+func (e *StreamIDError) Error() string {
+	return fmt.Sprintf("invalid stream ID %q: %s", e.StreamID, e.Msg)
+}
+
+// ParseStreamID - Parse a StreamID in the SRT access control syntax. Stream
+// IDs not starting with "#!::" are free-form and reported as an error, which
+// the caller may choose to ignore.
+// A mode other than request, publish and bidirectional is reported with the
+// RejectionReasonBadMode reason, any other syntax error with
+// RejectionReasonBadRequest.
+func ParseStreamID(streamid string) (*StreamID, error) {
+	if !strings.HasPrefix(streamid, streamIDPrefix) {
+		return nil, &StreamIDError{streamid, RejectionReasonBadRequest, "missing " + streamIDPrefix + " prefix"}
+	}
+
+	sid := &StreamID{}
+	seen := make(map[string]bool)
+	for _, pair := range strings.Split(streamid[len(streamIDPrefix):], ",") {
+		eq := strings.IndexByte(pair, '=')
+		if eq <= 0 {
+			return nil, &StreamIDError{streamid, RejectionReasonBadRequest, fmt.Sprintf("invalid key=value pair %q", pair)}
+		}
+		key, value := pair[:eq], pair[eq+1:]
+		if seen[key] {
+			return nil, &StreamIDError{streamid, RejectionReasonBadRequest, fmt.Sprintf("key %q given more than once", key)}
+		}
+		seen[key] = true
+
+		switch key {
+		case "r":
+			sid.Resource = value
+		case "m":
+			switch mode := StreamIDMode(value); mode {
+			case StreamIDModeRequest, StreamIDModePublish, StreamIDModeBidirectional:
+				sid.Mode = mode
+			default:
+				return nil, &StreamIDError{streamid, RejectionReasonBadMode, fmt.Sprintf("unknown mode %q", value)}
+			}
+		case "u":
+			sid.User = value
+		case "h":
+			sid.Host = value
+		case "s":
+			sid.Session = value
+		case "t":
+			switch typ := StreamIDType(value); typ {
+			case StreamIDTypeStream, StreamIDTypeFile, StreamIDTypeAuth:
+				sid.Type = typ
+			default:
+				return nil, &StreamIDError{streamid, RejectionReasonBadRequest, fmt.Sprintf("unknown type %q", value)}
+			}
+		default:
+			if sid.Custom == nil {
+				sid.Custom = make(map[string]string)
+			}
+			sid.Custom[key] = value
+		}
+	}
+	return sid, nil
+}
+
+// String - Render the StreamID in the SRT access control syntax, standard
+// keys first and custom keys sorted
+func (sid StreamID) String() string {
+	var pairs []string
+	add := func(key, value string) {
+		if value != "" {
+			pairs = append(pairs, key+"="+value)
+		}
+	}
+	add("r", sid.Resource)
+	add("m", string(sid.Mode))
+	add("u", sid.User)
+	add("h", sid.Host)
+	add("s", sid.Session)
+	add("t", string(sid.Type))
+
+	keys := make([]string, 0, len(sid.Custom))
+	for k := range sid.Custom {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		pairs = append(pairs, k+"="+sid.Custom[k])
+	}
+	return streamIDPrefix + strings.Join(pairs, ",")
+}
+
+// StreamIDRejectionReason - Return the rejection reason of a *StreamIDError,
+// such as the ones returned by ParseStreamID, to set with SetRejectReason in a
+// ListenCallbackFunc. Other errors map to RejectionReasonBadRequest.
+func StreamIDRejectionReason(err error) int {
+	var sidErr *StreamIDError
+	if errors.As(err, &sidErr) {
+		return sidErr.Reason
+	}
+	return RejectionReasonBadRequest
+}
@@ -0,0 +1,80 @@
+package srtgo
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestParseStreamID(t *testing.T) {
+	for _, tc := range []struct {
+		streamid string
+		expected StreamID
+	}{
+		{"#!::r=live/camera1", StreamID{Resource: "live/camera1"}},
+		{"#!::r=live,m=publish,u=alice,h=example.com,s=1234,t=stream",
+			StreamID{Resource: "live", Mode: StreamIDModePublish, User: "alice", Host: "example.com", Session: "1234", Type: StreamIDTypeStream}},
+		{"#!::m=request,t=file,u=",
+			StreamID{Mode: StreamIDModeRequest, Type: StreamIDTypeFile}},
+		{"#!::r=a,token=x=y,quality=hd",
+			StreamID{Resource: "a", Custom: map[string]string{"token": "x=y", "quality": "hd"}}},
+	} {
+		sid, err := ParseStreamID(tc.streamid)
+		if err != nil {
+			t.Errorf("%s: %v", tc.streamid, err)
+			continue
+		}
+		if !reflect.DeepEqual(*sid, tc.expected) {
+			t.Errorf("%s: got %+v, expected %+v", tc.streamid, *sid, tc.expected)
+		}
+	}
+}
+
+func TestParseStreamIDErrors(t *testing.T) {
+	for _, tc := range []struct {
+		streamid string
+		reason   int
+	}{
+		{"live/camera1", RejectionReasonBadRequest},
+		{"#!::", RejectionReasonBadRequest},
+		{"#!::r=a,,u=b", RejectionReasonBadRequest},
+		{"#!::r", RejectionReasonBadRequest},
+		{"#!::=a", RejectionReasonBadRequest},
+		{"#!::r=a,r=b", RejectionReasonBadRequest},
+		{"#!::t=video", RejectionReasonBadRequest},
+		{"#!::r=a,m=play", RejectionReasonBadMode},
+	} {
+		_, err := ParseStreamID(tc.streamid)
+		if err == nil {
+			t.Errorf("%s: expected an error", tc.streamid)
+			continue
+		}
+		if reason := StreamIDRejectionReason(err); reason != tc.reason {
+			t.Errorf("%s: got rejection reason %d, expected %d", tc.streamid, reason, tc.reason)
+		}
+		if reason := StreamIDRejectionReason(fmt.Errorf("wrapped: %w", err)); reason != tc.reason {
+			t.Errorf("%s: got rejection reason %d for wrapped error, expected %d", tc.streamid, reason, tc.reason)
+		}
+	}
+}
+
+func TestStreamIDString(t *testing.T) {
+	sid := StreamID{
+		Resource: "live",
+		Mode:     StreamIDModePublish,
+		User:     "alice",
+		Custom:   map[string]string{"z": "1", "a": "2"},
+	}
+	const expected = "#!::r=live,m=publish,u=alice,a=2,z=1"
+	if s := sid.String(); s != expected {
+		t.Fatalf("got %q, expected %q", s, expected)
+	}
+
+	parsed, err := ParseStreamID(sid.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(*parsed, sid) {
+		t.Errorf("round trip gave %+v, expected %+v", *parsed, sid)
+	}
+}
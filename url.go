@@ -0,0 +1,148 @@
+package srtgo
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// urlScheme is the scheme of SRT URLs
+const urlScheme = "srt"
+
+// Options understood by NewSrtSocket itself, on top of the ones in SocketOptions
+var localOptions = []string{"mode", "blocking", "linger", "pktsize", "adapter", "localport"}
+
+// Alternative spellings of query parameters used by ffmpeg and
+// srt-live-transmit, mapped to the option names used by srtgo
+var urlOptionAliases = map[string]string{
+	"port":            "localport",
+	"pkt_size":        "payloadsize",
+	"payload_size":    "payloadsize",
+	"ffs":             "fc",
+	"smoother":        "congestion",
+	"connect_timeout": "conntimeo",
+}
+
+// isKnownOption - Return whether name is an option accepted by NewSrtSocket
+func isKnownOption(name string) bool {
+	for _, so := range SocketOptions {
+		if so.name == name {
+			return true
+		}
+	}
+	for _, lo := range localOptions {
+		if lo == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseSrtURL - Parse a srt://host:port?key=value&... URL into the host, port
+// and options accepted by NewSrtSocket.
+//
+// Query parameters use the srt-live-transmit conventions: the keys are the
+// names in SocketOptions (plus "mode", "blocking", "linger", "pktsize",
+// "adapter" and "localport") and time values are in milliseconds. The local
+// port may also be given as "port", like srt-live-transmit does, and the
+// ffmpeg spellings "pkt_size", "payload_size", "ffs", "smoother" and
+// "connect_timeout" are accepted as aliases. Unknown keys are reported as an
+// error.
+// An empty host ("srt://:8090") selects listener mode by default.
+func ParseSrtURL(rawurl string) (string, uint16, map[string]string, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return "", 0, nil, err
+	}
+	if u.Scheme != urlScheme {
+		return "", 0, nil, fmt.Errorf("invalid scheme %q, expected %q", u.Scheme, urlScheme)
+	}
+	if u.Opaque != "" || u.User != nil || (u.Path != "" && u.Path != "/") {
+		return "", 0, nil, fmt.Errorf("invalid SRT URL %q", rawurl)
+	}
+
+	p := u.Port()
+	if p == "" {
+		return "", 0, nil, fmt.Errorf("missing port in SRT URL %q", rawurl)
+	}
+	port, err := strconv.ParseUint(p, 10, 16)
+	if err != nil {
+		return "", 0, nil, fmt.Errorf("invalid port %q: %w", p, err)
+	}
+
+	query, err := url.ParseQuery(u.RawQuery)
+	if err != nil {
+		return "", 0, nil, err
+	}
+
+	options := make(map[string]string)
+	var unknown []string
+	for key, values := range query {
+		name := strings.ToLower(key)
+		if alias, ok := urlOptionAliases[name]; ok {
+			name = alias
+		}
+		if !isKnownOption(name) {
+			unknown = append(unknown, key)
+			continue
+		}
+		if _, exists := options[name]; exists || len(values) > 1 {
+			return "", 0, nil, fmt.Errorf("option %q given more than once", name)
+		}
+		options[name] = values[0]
+	}
+	if len(unknown) > 0 {
+		return "", 0, nil, fmt.Errorf("unknown options in SRT URL: %s", strings.Join(unknown, ", "))
+	}
+
+	return u.Hostname(), uint16(port), options, nil
+}
+
+// NewSrtSocketFromURL - Create a new SRT Socket configured from a srt:// URL.
+// See ParseSrtURL for the accepted format.
+func NewSrtSocketFromURL(rawurl string) (*SrtSocket, error) {
+	host, port, options, err := ParseSrtURL(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	//rawurl is not part of the error, it may hold the passphrase
+	return NewSrtSocketE(host, port, options)
+}
+
+// redactedPassphrase replaces the passphrase in the URLs rendered by URL
+const redactedPassphrase = "xxxxx"
+
+// URL - Render the configuration of the socket as a srt:// URL, which
+// ParseSrtURL turns back into the same host, port and options.
+//
+// Like url.URL.Redacted does for passwords, the passphrase is replaced by
+// "xxxxx" so that the URL can be logged; the passphrase itself has to be
+// given again to rebuild the socket.
+func (s *SrtSocket) URL() string {
+	query := url.Values{}
+	for k, v := range s.options {
+		if k == "passphrase" {
+			v = redactedPassphrase
+		}
+		query.Set(k, v)
+	}
+	if _, ok := s.options["mode"]; !ok {
+		switch s.mode {
+		case ModeCaller:
+			query.Set("mode", "caller")
+		case ModeListener:
+			query.Set("mode", "listener")
+		case ModeRendezvouz:
+			query.Set("mode", "rendezvous")
+		}
+	}
+
+	u := url.URL{
+		Scheme:   urlScheme,
+		Host:     net.JoinHostPort(s.host, strconv.Itoa(int(s.port))),
+		RawQuery: query.Encode(),
+	}
+	return u.String()
+}
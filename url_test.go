@@ -0,0 +1,84 @@
+package srtgo
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseSrtURL(t *testing.T) {
+	for _, tc := range []struct {
+		url     string
+		host    string
+		port    uint16
+		options map[string]string
+	}{
+		{"srt://127.0.0.1:8090", "127.0.0.1", 8090, map[string]string{}},
+		{"srt://:8090?mode=listener", "", 8090, map[string]string{"mode": "listener"}},
+		{"srt://[::1]:9000?latency=200&streamid=%23%21%3A%3Ar%3Dlive", "::1", 9000,
+			map[string]string{"latency": "200", "streamid": "#!::r=live"}},
+		{"srt://example.com:1234?mode=caller&pkt_size=1316&smoother=live", "example.com", 1234,
+			map[string]string{"mode": "caller", "payloadsize": "1316", "congestion": "live"}},
+		{"srt://host:1/?Passphrase=secretsecret", "host", 1, map[string]string{"passphrase": "secretsecret"}},
+	} {
+		host, port, options, err := ParseSrtURL(tc.url)
+		if err != nil {
+			t.Errorf("%s: %v", tc.url, err)
+			continue
+		}
+		if host != tc.host || port != tc.port {
+			t.Errorf("%s: got %s:%d, expected %s:%d", tc.url, host, port, tc.host, tc.port)
+		}
+		if !reflect.DeepEqual(options, tc.options) {
+			t.Errorf("%s: got options %v, expected %v", tc.url, options, tc.options)
+		}
+	}
+}
+
+func TestParseSrtURLErrors(t *testing.T) {
+	for _, u := range []string{
+		"udp://127.0.0.1:8090",
+		"srt://127.0.0.1",
+		"srt://127.0.0.1:99999",
+		"srt://127.0.0.1:8090?latencyy=200",
+		"srt://127.0.0.1:8090?latency=200&latency=300",
+		"srt://127.0.0.1:8090/path",
+		"srt://user@127.0.0.1:8090",
+	} {
+		if _, _, _, err := ParseSrtURL(u); err == nil {
+			t.Errorf("%s: expected an error", u)
+		}
+	}
+}
+
+func TestSrtSocketURL(t *testing.T) {
+	InitSRT()
+
+	s, err := NewSrtSocketFromURL("srt://127.0.0.1:8090?latency=200&transtype=file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	expected := "srt://127.0.0.1:8090?latency=200&mode=caller&transtype=file"
+	if s.URL() != expected {
+		t.Errorf("got %s, expected %s", s.URL(), expected)
+	}
+
+	host, port, options, err := ParseSrtURL(s.URL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if host != "127.0.0.1" || port != 8090 || options["latency"] != "200" || options["mode"] != "caller" {
+		t.Errorf("URL did not round trip: %s:%d %v", host, port, options)
+	}
+
+	p, err := NewSrtSocketFromURL("srt://127.0.0.1:8090?passphrase=0123456789secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+	if u := p.URL(); strings.Contains(u, "secret") || !strings.Contains(u, "passphrase=xxxxx") {
+		t.Errorf("the passphrase is not redacted in %s", u)
+	}
+}
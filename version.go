@@ -0,0 +1,40 @@
+package srtgo
+
+// #cgo LDFLAGS: -lsrt
+// #include <srt/srt.h>
+import "C"
+
+import "fmt"
+
+// SrtVersion - SRT version number, encoded like SRT_VERSION_VALUE:
+// major<<16 | minor<<8 | patch
+type SrtVersion uint32
+
+// MakeSrtVersion - Encode a major.minor.patch SRT version
+func MakeSrtVersion(major, minor, patch int) SrtVersion {
+	return SrtVersion(major<<16 | minor<<8 | patch)
+}
+
+// Major - Return the major version number
+func (v SrtVersion) Major() int {
+	return int(v >> 16 & 0xff)
+}
+
+// Minor - Return the minor version number
+func (v SrtVersion) Minor() int {
+	return int(v >> 8 & 0xff)
+}
+
+// Patch - Return the patch version number
+func (v SrtVersion) Patch() int {
+	return int(v & 0xff)
+}
+
+func (v SrtVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major(), v.Minor(), v.Patch())
+}
+
+// LibVersion - Return the version of the libsrt linked at runtime (srt_getversion)
+func LibVersion() SrtVersion {
+	return SrtVersion(C.srt_getversion())
+}
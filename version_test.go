@@ -0,0 +1,76 @@
+package srtgo
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSrtVersion(t *testing.T) {
+	v := MakeSrtVersion(1, 5, 6)
+	if v != 0x010506 {
+		t.Errorf("expected 0x010506, got %#x", uint32(v))
+	}
+	if v.Major() != 1 || v.Minor() != 5 || v.Patch() != 6 {
+		t.Errorf("unexpected version components %d.%d.%d", v.Major(), v.Minor(), v.Patch())
+	}
+	if v.String() != "1.5.6" {
+		t.Errorf("expected 1.5.6, got %s", v)
+	}
+}
+
+func TestLibVersion(t *testing.T) {
+	if LibVersion() < MakeSrtVersion(1, 4, 2) {
+		t.Errorf("linked libsrt %s is older than the minimum supported 1.4.2", LibVersion())
+	}
+}
+
+func TestUnsupportedOption(t *testing.T) {
+	saved := optionMinVersion[SRTO_CRYPTOMODE]
+	defer func() { optionMinVersion[SRTO_CRYPTOMODE] = saved }()
+	optionMinVersion[SRTO_CRYPTOMODE] = MakeSrtVersion(99, 0, 0)
+
+	InitSRT()
+	a := NewSrtSocket("localhost", 8090, map[string]string{})
+	if a == nil {
+		t.Fatal("Could not create a srt socket")
+	}
+	defer a.Close()
+
+	err := a.SetSockOptInt(SRTO_CRYPTOMODE, 1)
+	var uerr *UnsupportedOptionError
+	if !errors.As(err, &uerr) {
+		t.Fatalf("expected an UnsupportedOptionError, got %v", err)
+	}
+	if uerr.Option != "cryptomode" || uerr.Required != MakeSrtVersion(99, 0, 0) {
+		t.Errorf("unexpected error contents: %v", uerr)
+	}
+
+	if b := NewSrtSocket("localhost", 8090, map[string]string{"cryptomode": "1"}); b != nil {
+		b.Close()
+		t.Error("creating a socket with an unsupported option should fail")
+	}
+	if b, err := NewSrtSocketE("localhost", 8090, map[string]string{"cryptomode": "1"}); !errors.As(err, &uerr) {
+		if b != nil {
+			b.Close()
+		}
+		t.Errorf("expected an UnsupportedOptionError from NewSrtSocketE, got %v", err)
+	}
+}
+
+func TestNewSocketWithNewerOptions(t *testing.T) {
+	InitSRT()
+	options := map[string]string{"ipv6only": "0", "retransmitalgo": "1", "drifttracer": "1"}
+	a := NewSrtSocket("localhost", 8090, options)
+	if a == nil {
+		t.Fatal("Could not create a srt socket")
+	}
+	defer a.Close()
+
+	v, err := a.GetSockOptInt(SRTO_RETRANSMITALGO)
+	if err != nil {
+		t.Error(err)
+	}
+	if v != 1 {
+		t.Errorf("expected SRTO_RETRANSMITALGO 1, got %d", v)
+	}
+}
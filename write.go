@@ -37,22 +37,54 @@ func srtSendMsg2Impl(u C.SRTSOCKET, buf []byte, msgctrl *C.SRT_MSGCTRL) (n int,
 }
 
 // Write data to the SRT socket
-func (s SrtSocket) Write(b []byte) (n int, err error) {
+func (s *SrtSocket) Write(b []byte) (n int, err error) {
+	return s.send(b, nil, nil)
+}
+
+// WriteMsg - Write a message to the SRT socket with message control
+// information: time to live, in-order delivery, source time and boundary.
+// Zero fields keep the libsrt defaults.
+func (s *SrtSocket) WriteMsg(b []byte, msg MsgCtrl) (int, error) {
+	mctrl := C.srt_msgctrl_default
+	if msg.TTL > 0 {
+		mctrl.msgttl = C.int(msg.TTL.Milliseconds())
+	}
+	if msg.InOrder {
+		mctrl.inorder = 1
+	}
+	if msg.Boundary != 0 {
+		mctrl.boundary = C.int(msg.Boundary)
+	}
+	if msg.SrcTime != 0 {
+		mctrl.srctime = C.int64_t(msg.SrcTime)
+	}
+	return s.send(b, &mctrl, nil)
+}
+
+// send - Send a message, giving up when done is closed
+func (s *SrtSocket) send(b []byte, mctrl *C.SRT_MSGCTRL, done <-chan struct{}) (int, error) {
+	if s.isClosed() {
+		return 0, &SrtSocketClosed{}
+	}
+	n, err := s.sendWait(b, mctrl, done)
+	return n, s.closedError(err)
+}
 
+func (s *SrtSocket) sendWait(b []byte, mctrl *C.SRT_MSGCTRL, done <-chan struct{}) (n int, err error) {
 	//Fastpath:
 	if !s.blocking {
 		s.pd.reset(ModeWrite)
 	}
-	n, err = srtSendMsg2Impl(s.socket, b, nil)
+	n, err = srtSendMsg2Impl(s.socket, b, mctrl)
 
 	for {
 		if !errors.Is(err, error(EAsyncSND)) || s.blocking {
 			return
 		}
-		err = s.pd.wait(ModeWrite)
+		err = s.pd.waitDone(ModeWrite, done)
 		if err != nil {
 			return
 		}
-		n, err = srtSendMsg2Impl(s.socket, b, nil)
+		n, err = srtSendMsg2Impl(s.socket, b, mctrl)
 	}
 }